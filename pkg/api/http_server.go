@@ -2,10 +2,20 @@ package api
 
 import (
 	"context"
+	"country-search-api/pkg/config"
 	"country-search-api/pkg/handler"
 	"country-search-api/pkg/logger"
+	"country-search-api/pkg/service/alias"
 	http_client "country-search-api/pkg/service/client"
 	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/dataset"
+	"country-search-api/pkg/service/flags"
+	"country-search-api/pkg/service/geo"
+	"country-search-api/pkg/service/ipdb"
+	"country-search-api/pkg/service/money"
+	"country-search-api/pkg/service/snapshot"
+	"country-search-api/pkg/service/webhook"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,24 +29,142 @@ import (
 // var restcountries = "https://restcountries.com/v3.1/name/{name}?fields=name,capital,currencies,population&fullText=true"
 
 func RegisterRoutes() {
+	cfg := config.Load()
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
 	router := gin.Default()
-	router.Use(TimeoutMiddleware(20 * time.Second))
+	// Every route but the NDJSON stream, which may outlive any fixed
+	// timeout, is bounded by TimeoutMiddleware.
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Log().Error("invalid trusted proxies:", "proxies", cfg.TrustedProxies, "error", err)
+		os.Exit(1)
+	}
+	timed := router.Group("", TimeoutMiddleware(20*time.Second))
 
-	defaultBaseURL := "https://restcountries.com/v3.1"
-	httpClient := http_client.NewHTTPClient(5*time.Second, nil)
-	counryService := country.NewCountryService(
-		httpClient,
-		defaultBaseURL,
-	)
+	var opts []country.Option
+	if cfg.AliasFile != "" {
+		aliases, err := alias.LoadFile(cfg.AliasFile)
+		if err == nil {
+			aliases, err = country.NormalizeAliases(aliases)
+		}
+		if err != nil {
+			logger.Log().Error("unable to load country aliases:", "file", cfg.AliasFile, "error", err)
+			os.Exit(1)
+		}
+		opts = append(opts, country.WithAliases(aliases))
+	}
+
+	var registryOpts []webhook.RegistryOption
+	if cfg.WebhooksAllowPrivate {
+		registryOpts = append(registryOpts, webhook.AllowPrivateDestinations())
+	}
+	registry := webhook.NewRegistry(registryOpts...)
+	if cfg.WebhooksFile != "" {
+		subs, err := webhook.LoadFile(cfg.WebhooksFile)
+		if err != nil {
+			logger.Log().Error("unable to load webhooks:", "file", cfg.WebhooksFile, "error", err)
+			os.Exit(1)
+		}
+		for _, sub := range subs {
+			if _, err := registry.Add(sub); err != nil {
+				logger.Log().Error("unable to register webhook:", "url", sub.URL, "error", err)
+				os.Exit(1)
+			}
+		}
+	}
+	dispatcher := webhook.NewDispatcher(registry, webhook.NewHTTPClient(10*time.Second, cfg.WebhooksAllowPrivate))
+	opts = append(opts, country.WithRefreshHook(func(update dataset.Update) {
+		go dispatcher.Notify(ctx, update)
+	}))
+
+	if cfg.BoundariesFile != "" {
+		boundaries, err := geo.LoadBoundaries(cfg.BoundariesFile)
+		if err != nil {
+			logger.Log().Error("unable to load country boundaries:", "file", cfg.BoundariesFile, "error", err)
+			os.Exit(1)
+		}
+		opts = append(opts, country.WithBoundaries(boundaries))
+	}
+
+	if cfg.IPDatabaseFile != "" {
+		db, err := ipdb.Open(cfg.IPDatabaseFile)
+		if err != nil {
+			logger.Log().Error("unable to load IP database:", "file", cfg.IPDatabaseFile, "error", err)
+			os.Exit(1)
+		}
+		go db.Watch(ctx, time.Minute)
+		opts = append(opts, country.WithIPDatabase(db))
+	}
+
+	if cfg.ExchangeRatesFile != "" {
+		rates, err := money.Open(cfg.ExchangeRatesFile)
+		if err != nil {
+			logger.Log().Error("unable to load exchange rates:", "file", cfg.ExchangeRatesFile, "error", err)
+			os.Exit(1)
+		}
+		go rates.Watch(ctx, time.Minute)
+		opts = append(opts, country.WithExchangeRates(rates))
+	}
+
+	switch {
+	case cfg.FlagsDir != "":
+		info, err := os.Stat(cfg.FlagsDir)
+		if err == nil && !info.IsDir() {
+			err = fmt.Errorf("%s is not a directory", cfg.FlagsDir)
+		}
+		if err != nil {
+			logger.Log().Error("unable to use flag image directory:", "dir", cfg.FlagsDir, "error", err)
+			os.Exit(1)
+		}
+		opts = append(opts, country.WithFlagImages(flags.Dir(cfg.FlagsDir)))
+	case cfg.DataSource != "offline":
+		opts = append(opts, country.WithFlagImages(flags.NewRemote(http_client.NewHTTPClient(10*time.Second, nil))))
+	}
+
+	counryService, err := newCountryService(cfg, opts...)
+	if err != nil {
+		logger.Log().Error("unable to set up country data source:", "source", cfg.DataSource, "error", err)
+		os.Exit(1)
+	}
 	countryHandler := handler.NewCountryHandler(counryService)
+	webhookHandler := handler.NewWebhookHandler(registry, dispatcher)
 
-	router.GET("/api/countries/search", countryHandler.GetCountry)
+	if cfg.RefreshInterval > 0 {
+		go country.RefreshEvery(ctx, counryService, cfg.RefreshInterval)
+	}
+
+	timed.GET("/api/countries", countryHandler.ListCountries)
+	timed.GET("/api/countries/search", countryHandler.GetCountry)
+	timed.GET("/api/countries/suggest", countryHandler.SuggestCountries)
+	timed.GET("/api/countries/changes", countryHandler.ListChanges)
+	timed.GET("/api/countries/by-ip", countryHandler.GetCountryByIP)
+	timed.GET("/api/countries/compare", countryHandler.CompareCountries)
+	timed.GET("/api/countries/:code", countryHandler.GetCountryByCode)
+	timed.GET("/api/countries/:code/time", countryHandler.GetLocalTime)
+	timed.GET("/api/countries/:code/flag", countryHandler.GetFlag)
+	timed.POST("/api/countries/batch", countryHandler.BatchCountries)
+	router.POST("/api/countries/stream", countryHandler.StreamCountries)
+	timed.GET("/api/borders/route", countryHandler.BorderRoute)
+	timed.GET("/api/borders/neighbors", countryHandler.BorderNeighbors)
+	timed.GET("/api/borders/components", countryHandler.BorderComponents)
+	timed.GET("/api/geo/distance", countryHandler.Distance)
+	timed.GET("/api/geo/nearest", countryHandler.NearestCountries)
+	timed.GET("/api/geo/reverse", countryHandler.CountryAt)
+	timed.GET("/api/time/overlap", countryHandler.BusinessOverlap)
+	timed.GET("/api/currency/convert", countryHandler.ConvertCurrency)
+	timed.GET("/api/stats/top", countryHandler.TopCountries)
+	timed.GET("/api/stats/:by", countryHandler.GetStats)
+
+	admin := timed.Group("/api/webhooks", handler.RequireAdminToken(cfg.AdminToken))
+	admin.POST("", webhookHandler.CreateWebhook)
+	admin.GET("", webhookHandler.ListWebhooks)
+	admin.DELETE("/:id", webhookHandler.DeleteWebhook)
+	admin.GET("/dead-letters", webhookHandler.ListDeadLetters)
 
 	srv := &http.Server{
-		Addr:              ":8080",
+		Addr:              cfg.Addr,
 		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second,
 		WriteTimeout:      5 * time.Second,
@@ -69,6 +197,28 @@ func RegisterRoutes() {
 	logger.Log().Warn("Server exiting")
 }
 
+// newCountryService builds the CountryService for the configured data source.
+func newCountryService(cfg config.Config, opts ...country.Option) (country.CountryService, error) {
+	httpClient := http_client.NewHTTPClient(5*time.Second, nil)
+	if cfg.DataSource == "live" {
+		return country.NewCountryService(httpClient, cfg.BaseURL, opts...), nil
+	}
+
+	data, err := snapshot.Load(cfg.SnapshotFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.DataSource {
+	case "offline":
+		return country.NewOfflineCountryService(data, opts...)
+	case "hybrid":
+		return country.NewHybridCountryService(httpClient, cfg.BaseURL, data, opts...)
+	default:
+		return nil, fmt.Errorf("unknown data source %q, want live, offline or hybrid", cfg.DataSource)
+	}
+}
+
 func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the service settings, read from the environment.
+type Config struct {
+	// Addr is the address the HTTP server listens on.
+	Addr string
+	// BaseURL is the REST Countries v3.1 API root.
+	BaseURL string
+	// AliasFile optionally points at a JSON object of extra country aliases.
+	AliasFile string
+	// DataSource is "live", "offline" or "hybrid".
+	DataSource string
+	// SnapshotFile optionally replaces the embedded dataset snapshot used by
+	// the offline and hybrid data sources.
+	SnapshotFile string
+	// RefreshInterval is how often the full dataset is reloaded; zero
+	// disables scheduled refreshes.
+	RefreshInterval time.Duration
+	// WebhooksFile optionally points at a JSON array of webhook
+	// subscriptions registered at startup.
+	WebhooksFile string
+	// WebhooksAllowPrivate lets webhooks be delivered to loopback,
+	// link-local and private addresses.
+	WebhooksAllowPrivate bool
+	// AdminToken is the bearer token the webhook management routes require;
+	// they refuse every request while it is empty.
+	AdminToken string
+	// BoundariesFile optionally points at a GeoJSON FeatureCollection of
+	// country boundaries used for reverse geocoding.
+	BoundariesFile string
+	// IPDatabaseFile optionally points at a MaxMind .mmdb or CSV range
+	// database used to look up countries by IP address.
+	IPDatabaseFile string
+	// ExchangeRatesFile optionally points at a JSON file of exchange rates
+	// used for currency conversion.
+	ExchangeRatesFile string
+	// FlagsDir optionally points at a directory of flag images named after
+	// the alpha-2 code, such as de.svg and de.png. Without it, images are
+	// fetched from the upstream flag URLs and cached.
+	FlagsDir string
+	// TrustedProxies lists the proxy addresses or CIDR ranges whose
+	// X-Forwarded-For headers are honored; none are trusted by default.
+	TrustedProxies []string
+}
+
+// Load reads the configuration from the environment, falling back to the
+// defaults for unset variables.
+func Load() Config {
+	return Config{
+		Addr:         getenv("ADDR", ":8080"),
+		BaseURL:      getenv("RESTCOUNTRIES_BASE_URL", "https://restcountries.com/v3.1"),
+		AliasFile:    os.Getenv("COUNTRY_ALIASES_FILE"),
+		DataSource:   getenv("COUNTRY_DATA_SOURCE", "live"),
+		SnapshotFile: os.Getenv("COUNTRY_SNAPSHOT_FILE"),
+
+		RefreshInterval: getDuration("COUNTRY_REFRESH_INTERVAL", 24*time.Hour),
+		WebhooksFile:    os.Getenv("COUNTRY_WEBHOOKS_FILE"),
+		BoundariesFile:  os.Getenv("COUNTRY_BOUNDARIES_FILE"),
+		IPDatabaseFile:  os.Getenv("COUNTRY_IP_DATABASE_FILE"),
+		TrustedProxies:  getList("TRUSTED_PROXIES"),
+
+		WebhooksAllowPrivate: getBool("COUNTRY_WEBHOOKS_ALLOW_PRIVATE"),
+		AdminToken:           os.Getenv("COUNTRY_ADMIN_TOKEN"),
+
+		ExchangeRatesFile: os.Getenv("COUNTRY_EXCHANGE_RATES_FILE"),
+		FlagsDir:          os.Getenv("COUNTRY_FLAGS_DIR"),
+	}
+}
+
+func getenv(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getDuration parses key as a time.Duration, falling back when it is unset
+// or malformed.
+func getDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d < 0 {
+		return fallback
+	}
+	return d
+}
+
+// getBool parses key as a boolean, treating unset or malformed values as
+// false.
+func getBool(key string) bool {
+	b, err := strconv.ParseBool(os.Getenv(key))
+	return err == nil && b
+}
+
+// getList splits key on commas, dropping empty entries.
+func getList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
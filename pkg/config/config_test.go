@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("ADDR", "")
+	t.Setenv("RESTCOUNTRIES_BASE_URL", "")
+	t.Setenv("COUNTRY_ALIASES_FILE", "")
+	t.Setenv("COUNTRY_DATA_SOURCE", "")
+	t.Setenv("COUNTRY_SNAPSHOT_FILE", "")
+	t.Setenv("COUNTRY_REFRESH_INTERVAL", "")
+	t.Setenv("COUNTRY_WEBHOOKS_FILE", "")
+	t.Setenv("COUNTRY_BOUNDARIES_FILE", "")
+	t.Setenv("COUNTRY_IP_DATABASE_FILE", "")
+	t.Setenv("TRUSTED_PROXIES", "")
+	t.Setenv("COUNTRY_EXCHANGE_RATES_FILE", "")
+	t.Setenv("COUNTRY_FLAGS_DIR", "")
+	t.Setenv("COUNTRY_WEBHOOKS_ALLOW_PRIVATE", "")
+	t.Setenv("COUNTRY_ADMIN_TOKEN", "")
+
+	cfg := Load()
+
+	assert.Equal(t, ":8080", cfg.Addr)
+	assert.Equal(t, "https://restcountries.com/v3.1", cfg.BaseURL)
+	assert.Empty(t, cfg.AliasFile)
+	assert.Equal(t, "live", cfg.DataSource)
+	assert.Empty(t, cfg.SnapshotFile)
+	assert.Equal(t, 24*time.Hour, cfg.RefreshInterval)
+	assert.Empty(t, cfg.WebhooksFile)
+	assert.Empty(t, cfg.BoundariesFile)
+	assert.Empty(t, cfg.IPDatabaseFile)
+	assert.Empty(t, cfg.TrustedProxies)
+	assert.Empty(t, cfg.ExchangeRatesFile)
+	assert.Empty(t, cfg.FlagsDir)
+	assert.False(t, cfg.WebhooksAllowPrivate)
+	assert.Empty(t, cfg.AdminToken)
+}
+
+func TestLoad_FromEnvironment(t *testing.T) {
+	t.Setenv("ADDR", ":9090")
+	t.Setenv("COUNTRY_ALIASES_FILE", "/etc/country-search/aliases.json")
+	t.Setenv("COUNTRY_DATA_SOURCE", "offline")
+	t.Setenv("COUNTRY_SNAPSHOT_FILE", "/var/lib/country-search/countries.json")
+	t.Setenv("COUNTRY_REFRESH_INTERVAL", "0")
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1,")
+	t.Setenv("COUNTRY_FLAGS_DIR", "/usr/share/country-search/flags")
+	t.Setenv("COUNTRY_WEBHOOKS_ALLOW_PRIVATE", "true")
+	t.Setenv("COUNTRY_ADMIN_TOKEN", "s3cret")
+
+	cfg := Load()
+
+	assert.Equal(t, ":9090", cfg.Addr)
+	assert.Equal(t, "/etc/country-search/aliases.json", cfg.AliasFile)
+	assert.Equal(t, "offline", cfg.DataSource)
+	assert.Equal(t, "/var/lib/country-search/countries.json", cfg.SnapshotFile)
+	assert.Zero(t, cfg.RefreshInterval)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.TrustedProxies)
+	assert.Equal(t, "/usr/share/country-search/flags", cfg.FlagsDir)
+	assert.True(t, cfg.WebhooksAllowPrivate)
+	assert.Equal(t, "s3cret", cfg.AdminToken)
+}
+
+func TestLoad_MalformedRefreshInterval(t *testing.T) {
+	t.Setenv("COUNTRY_REFRESH_INTERVAL", "daily")
+
+	assert.Equal(t, 24*time.Hour, Load().RefreshInterval)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/batch"
+	"country-search-api/pkg/service/country"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type batchRequest struct {
+	Items []batch.Item `json:"items"`
+}
+
+// BatchCountries resolves up to batch.MaxItems names and codes in one
+// request. Each item gets its own status, so one failure does not fail the
+// batch; the response itself is 200 whenever the request is well formed.
+func (ch *CountryHandler) BatchCountries(c *gin.Context) {
+	var req batchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `body must be {"items": [{"name": ...} or {"code": ...}]}`})
+		return
+	}
+	if len(req.Items) == 0 || len(req.Items) > batch.MaxItems {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("items must hold between 1 and %d entries", batch.MaxItems)})
+		return
+	}
+
+	policy, err := country.ParseTieBreak(c.Query("tiebreak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	opts, ok := parseView(c, c.Query("lang"))
+	if !ok {
+		return
+	}
+
+	results := ch.batch.Resolve(c.Request.Context(), req.Items, policy)
+
+	items := make([]gin.H, 0, len(results))
+	failed := 0
+	for _, r := range results {
+		item := batchItem(r, opts)
+		if r.Err != nil {
+			failed++
+		}
+		items = append(items, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   items,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+	})
+}
+
+// batchItem renders one result with the status and error body GetCountry
+// would have responded with for the same lookup.
+func batchItem(r batch.Result, opts viewOptions) gin.H {
+	if r.Err != nil {
+		status, body := errorResponse(r.Err)
+		body["index"] = r.Index
+		body["query"] = r.Item
+		body["status"] = status
+		return body
+	}
+
+	return gin.H{
+		"index":   r.Index,
+		"query":   r.Item,
+		"status":  http.StatusOK,
+		"country": opts.render(*r.Country),
+	}
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	mock_http_client "country-search-api/mock/ClientInf"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/country"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBatchCountriesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	samoa := `[{"name": {"common": "Samoa"}, "capital": ["Apia"], "population": 198410, "currencies": {"WST": {"symbol": "T"}}, "cca2": "WS", "cca3": "WSM"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/name/Samoa?fullText=true").Return([]byte(samoa), nil).Once()
+	mockClient.On("Get", mock.Anything, "/alpha?codes=NRU").Return(nil, http_client.ErrUpstream).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.POST("/api/countries/batch", ch.BatchCountries)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/countries/batch", strings.NewReader(`{"items": [{"name": "Samoa"}, {"code": "NRU"}, {"code": "N-R"}]}`))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Results []struct {
+			Index   int             `json:"index"`
+			Status  int             `json:"status"`
+			Error   string          `json:"error"`
+			Country json.RawMessage `json:"country"`
+		} `json:"results"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Equal(t, 2, resp.Failed)
+	assert.Equal(t, http.StatusOK, resp.Results[0].Status)
+	assert.JSONEq(t, `{"name": "Samoa", "capital": "Apia", "currency": "T", "currencyCode": "WST", "population": 198410}`, string(resp.Results[0].Country))
+	assert.Equal(t, http.StatusBadGateway, resp.Results[1].Status)
+	assert.Equal(t, "upstream service error", resp.Results[1].Error)
+	assert.Equal(t, http.StatusBadRequest, resp.Results[2].Status)
+	mockClient.AssertExpectations(t)
+}
+
+func TestBatchCountriesHandler_InvalidBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ch := NewCountryHandler(country.NewCountryService(new(mock_http_client.MockClientInf), ""))
+
+	r := gin.New()
+	r.POST("/api/countries/batch", ch.BatchCountries)
+
+	for _, body := range []string{`not json`, `{"items": []}`, `{"items": [` + strings.Repeat(`{"code": "FR"},`, 500) + `{"code": "FR"}]}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/countries/batch", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
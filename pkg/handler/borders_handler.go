@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/borders"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BorderRoute returns a shortest overland route between the countries
+// given by the from and to codes.
+func (ch *CountryHandler) BorderRoute(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+
+	route, err := ch.cs.BorderRoute(c.Request.Context(), from, to)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"crossings": len(route) - 1, "route": route})
+}
+
+// BorderNeighbors lists every country within k land-border crossings of the
+// country given by code.
+func (ch *CountryHandler) BorderNeighbors(c *gin.Context) {
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+	k, err := strconv.Atoi(c.DefaultQuery("k", "1"))
+	if err != nil || k < 1 || k > borders.MaxHops {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("k must be between 1 and %d", borders.MaxHops)})
+		return
+	}
+
+	neighbors, err := ch.cs.BorderNeighbors(c.Request.Context(), code, k)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, neighbors)
+}
+
+// BorderComponents lists the landmasses formed by countries connected by
+// land borders; countries without any are reported as islands.
+func (ch *CountryHandler) BorderComponents(c *gin.Context) {
+	components, err := ch.cs.BorderComponents(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, components)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBorderHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Laos"}, "capital": ["Vientiane"], "population": 7275556, "currencies": {"LAK": {"symbol": "₭"}}, "cca2": "LA", "cca3": "LAO", "borders": ["KHM", "THA"]},
+		{"name": {"common": "Cambodia"}, "capital": ["Phnom Penh"], "population": 16718971, "currencies": {"KHR": {"symbol": "៛"}}, "cca2": "KH", "cca3": "KHM", "borders": ["LAO"]},
+		{"name": {"common": "Thailand"}, "capital": ["Bangkok"], "population": 69799978, "currencies": {"THB": {"symbol": "฿"}}, "cca2": "TH", "cca3": "THA", "borders": ["LAO"]},
+		{"name": {"common": "Sri Lanka"}, "capital": ["Sri Jayawardenepura Kotte"], "population": 21919000, "currencies": {"LKR": {"symbol": "Rs"}}, "cca2": "LK", "cca3": "LKA"}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/borders/route", ch.BorderRoute)
+	r.GET("/api/borders/neighbors", ch.BorderNeighbors)
+	r.GET("/api/borders/components", ch.BorderComponents)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/borders/route?from=KH&to=TH", http.StatusOK, `"crossings":2`},
+		{"/api/borders/route?from=KH&to=LK", http.StatusNotFound, `"no overland route"`},
+		{"/api/borders/route?from=KH&to=XX", http.StatusNotFound, `"country not found"`},
+		{"/api/borders/route?from=KH", http.StatusBadRequest, `"from and to are required"`},
+		{"/api/borders/neighbors?code=KHM&k=2", http.StatusOK, `{"cca3":"THA","name":"Thailand","hops":2}`},
+		{"/api/borders/neighbors?code=KHM&k=0", http.StatusBadRequest, `"k must be between 1 and 10"`},
+		{"/api/borders/components", http.StatusOK, `{"size":1,"island":true,"countries":[{"cca3":"LKA","name":"Sri Lanka"}]}`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/batch"
+	"country-search-api/pkg/service/compare"
+	"country-search-api/pkg/service/country"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompareCountries lines up the countries in the comma-separated names
+// parameter side by side. The names are looked up concurrently; if any
+// fails, the response is that lookup's error with the name that caused it.
+func (ch *CountryHandler) CompareCountries(c *gin.Context) {
+	var items []batch.Item
+	for _, name := range strings.Split(c.Query("names"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			items = append(items, batch.Item{Name: name})
+		}
+	}
+	if len(items) < compare.MinCountries || len(items) > compare.MaxCountries {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("names must list between %d and %d countries", compare.MinCountries, compare.MaxCountries)})
+		return
+	}
+
+	policy, err := country.ParseTieBreak(c.Query("tiebreak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	countries := make([]models.CountryDetails, 0, len(items))
+	seen := map[string]bool{}
+	for _, r := range ch.batch.Resolve(c.Request.Context(), items, policy) {
+		if r.Err != nil {
+			status, body := errorResponse(r.Err)
+			body["name"] = r.Item.Name
+			c.JSON(status, body)
+			return
+		}
+		// The same country may be named twice, as in "USA,United States".
+		if !seen[r.Country.CCA3] {
+			seen[r.Country.CCA3] = true
+			countries = append(countries, *r.Country)
+		}
+	}
+	if len(countries) < compare.MinCountries {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "names must list at least two different countries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, compare.Countries(countries))
+}
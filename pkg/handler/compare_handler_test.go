@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/compare"
+	"country-search-api/pkg/service/country"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareCountries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Belgium"}, "capital": ["Brussels"], "population": 11555997, "area": 30528, "currencies": {"EUR": {"symbol": "€"}}, "languages": {"deu": "German", "fra": "French", "nld": "Dutch"}, "borders": ["FRA", "DEU", "LUX", "NLD"], "cca2": "BE", "cca3": "BEL"},
+		{"name": {"common": "Netherlands"}, "altSpellings": ["NL", "Holland"], "capital": ["Amsterdam"], "population": 16655799, "area": 41850, "currencies": {"EUR": {"symbol": "€"}}, "languages": {"nld": "Dutch"}, "borders": ["BEL", "DEU"], "cca2": "NL", "cca3": "NLD"},
+		{"name": {"common": "Luxembourg"}, "capital": ["Luxembourg"], "population": 632275, "area": 2586, "currencies": {"EUR": {"symbol": "€"}}, "languages": {"deu": "German", "fra": "French", "ltz": "Luxembourgish"}, "borders": ["BEL", "FRA", "DEU"], "cca2": "LU", "cca3": "LUX"}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries/compare", ch.CompareCountries)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/compare?names=Belgium,%20Holland,Luxembourg,Netherlands", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var cmp compare.Comparison
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &cmp))
+	assert.Len(t, cmp.Countries, 3) // Holland and Netherlands are the same country
+	assert.Equal(t, []string{"BEL", "NLD", "LUX"}, []string{cmp.Countries[0].CCA3, cmp.Countries[1].CCA3, cmp.Countries[2].CCA3})
+	assert.Equal(t, compare.Metrics[int]{Population: 1, Area: 1, Density: 1}, cmp.Countries[1].Ranks)
+	assert.InDelta(t, 1.441, cmp.Countries[1].Ratios.Population, 0.001)
+	assert.Equal(t, "EUR", cmp.Countries[2].Currencies[0].Code)
+	assert.Equal(t, []compare.Border{{From: "BEL", To: "NLD"}, {From: "BEL", To: "LUX"}}, cmp.SharedBorders)
+	assert.Len(t, cmp.SharedLanguages, 3)
+	assert.Equal(t, []string{"BEL", "LUX"}, cmp.SharedLanguages[0].Countries)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/countries/compare?names=Belgium", http.StatusBadRequest, `"names must list between 2 and 10 countries"`},
+		{"/api/countries/compare?names=a,b,c,d,e,f,g,h,i,j,k", http.StatusBadRequest, `"names must list between 2 and 10 countries"`},
+		{"/api/countries/compare?names=Belgium,Belgium", http.StatusBadRequest, `"names must list at least two different countries"`},
+		{"/api/countries/compare?names=Belgium,Atlantis", http.StatusNotFound, `"name":"Atlantis"`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+}
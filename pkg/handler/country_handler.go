@@ -2,20 +2,38 @@ package handler
 
 import (
 	"context"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/autocomplete"
+	"country-search-api/pkg/service/batch"
+	"country-search-api/pkg/service/borders"
 	http_client "country-search-api/pkg/service/client"
 	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/flags"
+	"country-search-api/pkg/service/geo"
+	"country-search-api/pkg/service/ipdb"
+	"country-search-api/pkg/service/locale"
+	"country-search-api/pkg/service/money"
+	"country-search-api/pkg/service/stats"
+	"country-search-api/pkg/service/timezone"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type CountryHandler struct {
-	cs country.CountryService
+	cs    country.CountryService
+	batch *batch.Resolver
 }
 
 func NewCountryHandler(cs country.CountryService) *CountryHandler {
-	return &CountryHandler{cs: cs}
+	return &CountryHandler{
+		cs:    cs,
+		batch: batch.NewResolver(cs, batch.DefaultWorkers),
+	}
 }
 
 func (ch *CountryHandler) GetCountry(c *gin.Context) {
@@ -23,26 +41,294 @@ func (ch *CountryHandler) GetCountry(c *gin.Context) {
 
 	countryName := c.DefaultQuery("name", "India")
 
-	country, err := ch.cs.GetCountryByName(c.Request.Context(), countryName)
+	match := c.DefaultQuery("match", "exact")
+	fields := c.Query("fields")
+	if fields != "" && match != "exact" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fields is only supported for exact matches"})
+		return
+	}
+
+	policy, err := country.ParseTieBreak(c.Query("tiebreak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	lookup := func(ctx context.Context, name string) (models.CountryDetails, error) {
+		return ch.cs.GetCountryDetailsByNameTieBreak(ctx, name, policy)
+	}
+	switch match {
+	case "exact":
+		if fields != "" {
+			ch.getCountryFields(c, countryName, strings.Split(fields, ","), policy)
+			return
+		}
+	case "fuzzy":
+		lookup = ch.cs.GetCountryDetailsByNameFuzzy
+	case "partial":
+		ch.searchCountries(c, countryName)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "match must be exact, fuzzy or partial"})
+		return
+	}
+
+	country, err := lookup(c.Request.Context(), countryName)
 	if err != nil {
-		switch {
-		case errors.Is(err, http_client.ErrNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "country not found"})
+		writeError(c, err)
+		return
+	}
 
-		case errors.Is(err, context.DeadlineExceeded):
-			c.JSON(http.StatusRequestTimeout, gin.H{"error": "request timeout"})
+	writeCountry(c, country)
+}
 
-		case errors.Is(err, http_client.ErrInvalidData):
-			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "could not validate country details"})
+// getCountryFields responds with only the requested fields of the country.
+func (ch *CountryHandler) getCountryFields(c *gin.Context, name string, fields []string, policy country.TieBreak) {
+	fields, err := country.NormalizeFields(fields)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
 
-		case errors.Is(err, http_client.ErrUpstream):
-			c.JSON(http.StatusBadGateway, gin.H{"error": "upstream service error"})
+	opts, ok := parseView(c, c.Query("lang"))
+	if !ok {
+		return
+	}
 
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to get country details"})
-		}
+	details, err := ch.cs.GetCountryFieldsByName(c.Request.Context(), name, fields, policy)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	projected, err := locale.Localize(details, opts.langs).Project(fields)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, projected)
+}
+
+func (ch *CountryHandler) searchCountries(c *gin.Context, query string) {
+	countries, err := ch.cs.SearchCountries(c.Request.Context(), query)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeCountries(c, countries)
+}
+
+// GetCountryByCode looks up one or more comma-separated ISO 3166-1 codes.
+// A single code returns an object and several codes return an array of the
+// countries found, with the codes not found listed in X-Missing-Codes.
+func (ch *CountryHandler) GetCountryByCode(c *gin.Context) {
+	codes := strings.Split(c.Param("code"), ",")
+
+	lookup, err := ch.cs.GetCountriesByCodes(c.Request.Context(), codes)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if len(codes) == 1 {
+		writeCountry(c, lookup.Countries[0])
+		return
+	}
+	if len(lookup.Missing) > 0 {
+		c.Header("X-Missing-Codes", strings.Join(lookup.Missing, ","))
+	}
+	writeCountries(c, lookup.Countries)
+}
+
+// SuggestCountries answers typeahead queries with up to limit countries
+// whose names start with q.
+func (ch *CountryHandler) SuggestCountries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > autocomplete.MaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", autocomplete.MaxLimit)})
+		return
+	}
+
+	suggestions, err := ch.cs.SuggestCountries(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// ListChanges returns the most recent dataset refreshes with the per-country
+// changes each one brought, newest first.
+func (ch *CountryHandler) ListChanges(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > country.HistorySize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", country.HistorySize)})
+		return
+	}
+
+	c.JSON(http.StatusOK, ch.cs.DatasetHistory(limit))
+}
+
+// viewOptions is how countries are rendered: in full or as summaries, and
+// with names in the caller's preferred languages.
+type viewOptions struct {
+	full  bool
+	langs []string
+}
+
+// parseView reads the view parameter and the language preferences from lang,
+// usually the lang query parameter, and Accept-Language. It responds with 400
+// and reports false when either is invalid.
+func parseView(c *gin.Context, lang string) (viewOptions, bool) {
+	var opts viewOptions
+	switch c.DefaultQuery("view", "summary") {
+	case "summary":
+	case "full":
+		opts.full = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "view must be summary or full"})
+		return viewOptions{}, false
+	}
+
+	langs, err := locale.Preferences(lang, c.GetHeader("Accept-Language"))
+	if err != nil {
+		writeError(c, err)
+		return viewOptions{}, false
+	}
+	c.Header("Vary", "Accept-Language")
+	opts.langs = langs
+	return opts, true
+}
+
+// render returns the full record or its summary, named in the preferred
+// language.
+func (opts viewOptions) render(country models.CountryDetails) any {
+	country = locale.Localize(country, opts.langs)
+	if opts.full {
+		return country
+	}
+	return country.Summary()
+}
+
+func writeCountry(c *gin.Context, country models.CountryDetails) {
+	opts, ok := parseView(c, c.Query("lang"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, opts.render(country))
+}
+
+func writeCountries(c *gin.Context, countries []models.CountryDetails) {
+	opts, ok := parseView(c, c.Query("lang"))
+	if !ok {
 		return
 	}
+	renderCountries(c, opts, countries)
+}
+
+func renderCountries(c *gin.Context, opts viewOptions, countries []models.CountryDetails) {
+	rendered := make([]any, 0, len(countries))
+	for _, country := range countries {
+		rendered = append(rendered, opts.render(country))
+	}
+	c.JSON(http.StatusOK, rendered)
+}
+
+// candidate is one entry of a 300 Multiple Choices response.
+type candidate struct {
+	Name       string `json:"name"`
+	Official   string `json:"official"`
+	CCA2       string `json:"cca2"`
+	CCA3       string `json:"cca3"`
+	Population int64  `json:"population"`
+	Link       string `json:"link"`
+}
+
+func writeError(c *gin.Context, err error) {
+	c.JSON(errorResponse(err))
+}
+
+// errorResponse maps a lookup error onto its status code and JSON body.
+func errorResponse(err error) (int, gin.H) {
+	var notFound *country.NotFoundError
+	var ambiguous *country.AmbiguousError
+	switch {
+	case errors.As(err, &ambiguous):
+		candidates := make([]candidate, 0, len(ambiguous.Candidates))
+		for _, d := range ambiguous.Candidates {
+			candidates = append(candidates, candidate{
+				Name:       d.Name.Common,
+				Official:   d.Name.Official,
+				CCA2:       d.CCA2,
+				CCA3:       d.CCA3,
+				Population: d.Population,
+				Link:       "/api/countries/" + d.CCA3,
+			})
+		}
+		return http.StatusMultipleChoices, gin.H{"error": "ambiguous country name", "candidates": candidates}
+
+	case errors.As(err, &notFound) && len(notFound.Suggestions) > 0:
+		return http.StatusNotFound, gin.H{"error": "country not found", "suggestions": notFound.Suggestions}
+
+	case errors.Is(err, country.ErrInvalidCode),
+		errors.Is(err, batch.ErrInvalidItem),
+		errors.Is(err, country.ErrInvalidList),
+		errors.Is(err, country.ErrInvalidField),
+		errors.Is(err, country.ErrInvalidTieBreak),
+		errors.Is(err, geo.ErrInvalidPoint),
+		errors.Is(err, geo.ErrInvalidAnchor),
+		errors.Is(err, timezone.ErrInvalidHours),
+		errors.Is(err, money.ErrInvalidAmount),
+		errors.Is(err, money.ErrUnknownCurrency),
+		errors.Is(err, stats.ErrInvalidQuery),
+		errors.Is(err, locale.ErrInvalidLanguage),
+		errors.Is(err, flags.ErrInvalidFormat):
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+
+	case errors.Is(err, flags.ErrNotAcceptable):
+		return http.StatusNotAcceptable, gin.H{"error": err.Error()}
 
-	c.JSON(http.StatusOK, country)
+	case errors.Is(err, geo.ErrNoCoordinates),
+		errors.Is(err, timezone.ErrNoTimezone):
+		return http.StatusUnprocessableEntity, gin.H{"error": err.Error()}
+
+	case errors.Is(err, geo.ErrNoBoundaries):
+		return http.StatusServiceUnavailable, gin.H{"error": "reverse geocoding is not configured"}
+
+	case errors.Is(err, money.ErrNoRate):
+		return http.StatusUnprocessableEntity, gin.H{"error": err.Error()}
+
+	case errors.Is(err, money.ErrNoRates):
+		return http.StatusServiceUnavailable, gin.H{"error": "currency conversion is not configured"}
+
+	case errors.Is(err, flags.ErrNoImages):
+		return http.StatusServiceUnavailable, gin.H{"error": "flag images are not configured"}
+
+	case errors.Is(err, flags.ErrNoFlag):
+		return http.StatusNotFound, gin.H{"error": err.Error()}
+
+	case errors.Is(err, ipdb.ErrNoDatabase):
+		return http.StatusServiceUnavailable, gin.H{"error": "IP lookup is not configured"}
+
+	case errors.Is(err, borders.ErrNoRoute):
+		return http.StatusNotFound, gin.H{"error": "no overland route"}
+
+	case errors.Is(err, http_client.ErrNotFound):
+		return http.StatusNotFound, gin.H{"error": "country not found"}
+
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusRequestTimeout, gin.H{"error": "request timeout"}
+
+	case errors.Is(err, http_client.ErrInvalidData):
+		return http.StatusUnprocessableEntity, gin.H{"error": "could not validate country details"}
+
+	case errors.Is(err, http_client.ErrUpstream):
+		return http.StatusBadGateway, gin.H{"error": "upstream service error"}
+
+	default:
+		return http.StatusInternalServerError, gin.H{"error": "unable to get country details"}
+	}
 }
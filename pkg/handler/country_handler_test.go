@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"context"
 	mock_http_client "country-search-api/mock/ClientInf"
+	http_client "country-search-api/pkg/service/client"
 	"country-search-api/pkg/service/country"
 	"net/http"
 	"net/http/httptest"
@@ -41,3 +43,390 @@ func TestGetUserHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), "New Delhi")
 }
+
+func TestGetCountryHandler_PartialMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[
+		{"name": {"common": "Guinea-Bissau"}, "capital": ["Bissau"], "population": 1967998, "currencies": {"XOF": {"symbol": "Fr"}}},
+		{"name": {"common": "Guinea"}, "capital": ["Conakry"], "population": 13132792, "currencies": {"GNF": {"symbol": "Fr"}}}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+
+	ncs := country.NewCountryService(mockClient, "")
+	ch := NewCountryHandler(ncs)
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=guin&match=partial", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Conakry")
+	assert.Contains(t, w.Body.String(), "Bissau")
+}
+
+func TestGetCountryHandler_InvalidMatchMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ch := NewCountryHandler(country.NewCountryService(new(mock_http_client.MockClientInf), ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=India&match=regex", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCountryByCodeHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[
+		{"name": {"common": "Chile"}, "capital": ["Santiago"], "population": 19116209, "currencies": {"CLP": {"symbol": "$"}}, "cca2": "CL", "cca3": "CHL", "ccn3": "152"},
+		{"name": {"common": "Kenya"}, "capital": ["Nairobi"], "population": 53771300, "currencies": {"KES": {"symbol": "Sh"}}, "cca2": "KE", "cca3": "KEN", "ccn3": "404"}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+	r.GET("/api/countries/:code", ch.GetCountryByCode)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/CL,404", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Santiago")
+	assert.Contains(t, w.Body.String(), "Nairobi")
+	assert.Empty(t, w.Header().Get("X-Missing-Codes"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/C1", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCountryByCodeHandler_MissingCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[{"name": {"common": "Uruguay"}, "capital": ["Montevideo"], "population": 3473727, "currencies": {"UYU": {"symbol": "$"}}, "cca2": "UY", "cca3": "URY", "ccn3": "858"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/:code", ch.GetCountryByCode)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/QQ,URY,998", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Montevideo")
+	assert.Equal(t, "QQ,998", w.Header().Get("X-Missing-Codes"))
+}
+
+func TestGetCountryHandler_FullView(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[{"name": {"common": "Zimbabwe", "official": "Republic of Zimbabwe"}, "capital": ["Harare"], "population": 14862927, "currencies": {"BWP": {"name": "Botswana pula", "symbol": "P"}, "USD": {"name": "United States dollar", "symbol": "$"}}, "cca3": "ZWE"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Zimbabwe&view=full", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"official":"Republic of Zimbabwe"`)
+	assert.Contains(t, w.Body.String(), `"code":"USD"`)
+}
+
+func TestGetCountryHandler_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[{"name": {"common": "Slovenia", "official": "Republic of Slovenia", "nativeName": {"slv": {"common": "Slovenija", "official": "Republika Slovenija"}}}, "capital": ["Ljubljana"], "population": 2100126, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "SVN", "translations": {"deu": {"common": "Slowenien", "official": "Republik Slowenien"}, "fra": {"common": "Slovénie", "official": "République de Slovénie"}}}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil)
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Slovenia", nil)
+	req.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	assert.Contains(t, w.Body.String(), `"name":"Slowenien"`)
+
+	// lang takes precedence over the header.
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Slovenia&lang=fr&view=full", nil)
+	req.Header.Set("Accept-Language", "de")
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"official":"République de Slovénie"`)
+
+	// Languages the country has no name in fall back to English.
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Slovenia&lang=ja", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Slovenia"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Slovenia&lang=not%20a%20language", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCountryHandler_FieldProjection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[{"name": {"common": "Nauru", "official": "Republic of Nauru"}, "flag": "🇳🇷", "flags": {"svg": "https://flagcdn.com/nr.svg"}}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Nauru&fields=name,flag", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"name": {"common": "Nauru", "official": "Republic of Nauru"},
+		"flag": {"emoji": "🇳🇷", "svg": "https://flagcdn.com/nr.svg"}
+	}`, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Nauru&fields=name,gdp", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCountryHandler_NotFoundSuggestions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	all := `[{"name": {"common": "Germany", "official": "Federal Republic of Germany"}, "capital": ["Berlin"], "population": 83240525, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "DEU"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/name/Germny?fullText=true").Return(nil, http_client.ErrNotFound)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(all), nil)
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Germny", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"suggestions":[{"name":"Germany","cca3":"DEU"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Germny&match=fuzzy", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Berlin")
+}
+
+func TestSuggestCountriesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	all := `[
+		{"name": {"common": "Malta"}, "capital": ["Valletta"], "population": 525285, "currencies": {"EUR": {"symbol": "€"}}, "cca2": "MT", "cca3": "MLT"},
+		{"name": {"common": "Malaysia"}, "capital": ["Kuala Lumpur"], "population": 32365998, "currencies": {"MYR": {"symbol": "RM"}}, "cca2": "MY", "cca3": "MYS"}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(all), nil).Times(3) // one call per field chunk
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/suggest", ch.SuggestCountries)
+
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/api/countries/suggest?q=mal&limit=5", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `[{"name":"Malaysia"`)
+	}
+	mockClient.AssertExpectations(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/suggest?q=mal&limit=0", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCountryHandler_AliasResolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[{"name": {"common": "Côte d'Ivoire"}, "capital": ["Yamoussoukro"], "population": 26378275, "currencies": {"XOF": {"symbol": "Fr"}}, "cca2": "CI", "cca3": "CIV", "ccn3": "384"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/alpha?codes=CIV").Return([]byte(body), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, "", country.WithAliases(map[string]string{"Ivory Coast": "CIV"})))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Ivory%20Coast", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"resolvedFrom":{"query":"Ivory Coast","via":"alias","name":"Côte d'Ivoire","cca3":"CIV"}`)
+}
+
+func TestGetCountryHandler_Ambiguous(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `[
+		{"name": {"common": "Republic of the Congo", "official": "Republic of the Congo"}, "capital": ["Brazzaville"], "population": 5657000, "currencies": {"XAF": {"symbol": "Fr"}}, "cca2": "CG", "cca3": "COG"},
+		{"name": {"common": "DR Congo", "official": "Democratic Republic of the Congo"}, "capital": ["Kinshasa"], "population": 108407721, "currencies": {"CDF": {"symbol": "FC"}}, "cca2": "CD", "cca3": "COD"}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil)
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.GET("/api/countries/search", ch.GetCountry)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Congos", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMultipleChoices, w.Code)
+	assert.Contains(t, w.Body.String(), `"link":"/api/countries/COD"`)
+	assert.Contains(t, w.Body.String(), `"link":"/api/countries/COG"`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Congos&tiebreak=populous", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Kinshasa")
+
+	// A projection answers the same as the full lookup.
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Congos&fields=capitals", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMultipleChoices, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Congos&fields=capitals&tiebreak=populous", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"capitals": ["Kinshasa"]}`, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/search?name=Congos&tiebreak=coin", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListChangesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	before := `[{"name": {"common": "Fiji"}, "capital": ["Suva"], "population": 896444, "currencies": {"FJD": {"symbol": "$"}}, "cca2": "FJ", "cca3": "FJI"}]`
+	after := `[{"name": {"common": "Fiji"}, "capital": ["Suva"], "population": 924610, "currencies": {"FJD": {"symbol": "$"}}, "cca2": "FJ", "cca3": "FJI"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(before), nil).Times(3)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(after), nil).Times(3)
+
+	cs := country.NewCountryService(mockClient, "")
+	for range 2 {
+		_, err := cs.RefreshDataset(context.Background())
+		assert.NoError(t, err)
+	}
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries/changes", ch.ListChanges)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/changes?limit=1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"fields":[{"field":"population","old":896444,"new":924610}]`)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/changes?limit=1000", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/money"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConvertCurrency converts amount between two currencies, each given either
+// as an ISO 4217 code (from, to) or as the currency of a country given by
+// code or name (fromCountry, toCountry).
+func (ch *CountryHandler) ConvertCurrency(c *gin.Context) {
+	from, ok := currencyRef(c, "from")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of from and fromCountry is required"})
+		return
+	}
+	to, ok := currencyRef(c, "to")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of to and toCountry is required"})
+		return
+	}
+	amount, err := money.ParseDecimal(c.DefaultQuery("amount", "1"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	conversion, err := ch.cs.ConvertCurrency(c.Request.Context(), amount, from, to)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conversion)
+}
+
+// currencyRef reads the currency code param or the country param+"Country",
+// reporting false unless exactly one is set.
+func currencyRef(c *gin.Context, param string) (country.CurrencyRef, bool) {
+	ref := country.CurrencyRef{Code: c.Query(param), Country: c.Query(param + "Country")}
+	return ref, (ref.Code == "") != (ref.Country == "")
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/money"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertCurrencyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Norway"}, "capital": ["Oslo"], "population": 5379475, "currencies": {"NOK": {"name": "Norwegian krone", "symbol": "kr"}}, "cca2": "NO", "cca3": "NOR"},
+		{"name": {"common": "Bahrain"}, "capital": ["Manama"], "population": 1701583, "currencies": {"BHD": {"name": "Bahraini dinar", "symbol": ".د.ب"}}, "cca2": "BH", "cca3": "BHR"}
+	]`
+	rates := money.Static{Base: "USD", Quotes: map[string]money.Decimal{
+		"USD": money.MustParseDecimal("1"),
+		"NOK": money.MustParseDecimal("10.5"),
+		"BHD": money.MustParseDecimal("0.376"),
+	}}
+	cs, err := country.NewOfflineCountryService([]byte(snapshot), country.WithExchangeRates(rates))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/currency/convert", ch.ConvertCurrency)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/currency/convert?amount=99.99&from=USD&toCountry=Norway", http.StatusOK, `"to":{"amount":"1049.90","currency":"NOK","country":"Norway"}`},
+		{"/api/currency/convert?amount=1000&fromCountry=NO&toCountry=BHR", http.StatusOK, `"to":{"amount":"35.810","currency":"BHD","country":"Bahrain"}`},
+		{"/api/currency/convert?from=USD&to=NOK", http.StatusOK, `"rate":"10.5","base":"USD"`},
+		{"/api/currency/convert?amount=1/3&from=USD&to=NOK", http.StatusBadRequest, `"invalid decimal amount`},
+		{"/api/currency/convert?from=USD&to=XYZ", http.StatusBadRequest, `"unknown currency`},
+		{"/api/currency/convert?from=USD&to=EUR", http.StatusUnprocessableEntity, `"no exchange rate for EUR"`},
+		{"/api/currency/convert?from=USD&to=NOK&toCountry=NO", http.StatusBadRequest, `"exactly one of to and toCountry is required"`},
+		{"/api/currency/convert?to=NOK", http.StatusBadRequest, `"exactly one of from and fromCountry is required"`},
+		{"/api/currency/convert?from=USD&toCountry=XXX", http.StatusNotFound, `"country not found"`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/flags"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flagCacheControl lets clients and proxies keep a flag for a year; the
+// ETag lets them revalidate it cheaply after that.
+const flagCacheControl = "public, max-age=31536000"
+
+// GetFlag returns the flag of the country given by code: its emoji as JSON,
+// or its SVG or PNG image, negotiated from the Accept header unless format
+// is given.
+func (ch *CountryHandler) GetFlag(c *gin.Context) {
+	c.Header("Vary", "Accept")
+
+	format, err := flags.Negotiate(c.GetHeader("Accept"))
+	if f := c.Query("format"); f != "" {
+		format, err = flags.ParseFormat(f)
+	}
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	var body []byte
+	if format == flags.EmojiFormat {
+		flag, err := ch.cs.FlagEmoji(c.Request.Context(), c.Param("code"))
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		if body, err = json.Marshal(flag); err != nil {
+			writeError(c, err)
+			return
+		}
+	} else {
+		img, err := ch.cs.FlagImage(c.Request.Context(), c.Param("code"), format)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		body = img.Data
+	}
+
+	etag := flags.ETag(body)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", flagCacheControl)
+	if matchesETag(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, format.ContentType(), body)
+}
+
+// matchesETag reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 requires.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/flags"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFlagHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Malta"}, "capital": ["Valletta"], "population": 525285, "currencies": {"EUR": {"symbol": "€"}}, "cca2": "MT", "cca3": "MLT", "flags": {"png": "https://flagcdn.com/w320/mt.png", "svg": "https://flagcdn.com/mt.svg"}},
+		{"name": {"common": "Tonga"}, "capital": ["Nuku'alofa"], "population": 105697, "currencies": {"TOP": {"symbol": "T$"}}, "cca2": "TO", "cca3": "TON"}
+	]`
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 900 600"/>`)
+	png := []byte("\x89PNG\r\n\x1a\nIHDR")
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mt.svg"), svg, 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mt.png"), png, 0o600))
+
+	cs, err := country.NewOfflineCountryService([]byte(snapshot), country.WithFlagImages(flags.Dir(dir)))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries/:code/flag", ch.GetFlag)
+
+	tests := []struct {
+		url         string
+		accept      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{"/api/countries/MLT/flag", "", http.StatusOK, "application/json", `"emoji":"🇲🇹"`},
+		{"/api/countries/mt/flag", "image/png", http.StatusOK, "image/png", string(png)},
+		{"/api/countries/MT/flag", "image/*", http.StatusOK, "image/svg+xml", string(svg)},
+		{"/api/countries/MT/flag", "image/svg+xml;q=0.5, image/png", http.StatusOK, "image/png", string(png)},
+		{"/api/countries/MT/flag?format=svg", "application/json", http.StatusOK, "image/svg+xml", string(svg)},
+		{"/api/countries/MT/flag", "text/html", http.StatusNotAcceptable, "application/json", `"no acceptable flag format`},
+		{"/api/countries/MT/flag?format=gif", "", http.StatusBadRequest, "application/json", `"invalid flag format`},
+		{"/api/countries/TON/flag", "", http.StatusOK, "application/json", `"emoji":"🇹🇴"`},
+		{"/api/countries/TON/flag", "image/png", http.StatusNotFound, "application/json", `"flag not found: no png image for Tonga"`},
+		{"/api/countries/XXX/flag", "", http.StatusNotFound, "application/json", `"country not found"`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Header().Get("Content-Type"), tt.contentType, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+		assert.Equal(t, "Accept", w.Header().Get("Vary"), tt.url)
+	}
+}
+
+func TestGetFlagHandler_ConditionalRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[{"name": {"common": "Nepal"}, "capital": ["Kathmandu"], "population": 29136808, "currencies": {"NPR": {"symbol": "₨"}}, "cca2": "NP", "cca3": "NPL"}]`
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 726 885"/>`)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "np.svg"), svg, 0o600))
+
+	cs, err := country.NewOfflineCountryService([]byte(snapshot), country.WithFlagImages(flags.Dir(dir)))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries/:code/flag", ch.GetFlag)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/NP/flag?format=svg", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, flags.ETag(svg), w.Header().Get("ETag"))
+	assert.Equal(t, "public, max-age=31536000", w.Header().Get("Cache-Control"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/NP/flag?format=svg", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+flags.ETag(svg))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, flags.ETag(svg), w.Header().Get("ETag"))
+
+	// The emoji is a different representation with its own tag.
+	req = httptest.NewRequest(http.MethodGet, "/api/countries/NP/flag", nil)
+	req.Header.Set("If-None-Match", flags.ETag(svg))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, flags.ETag(svg), w.Header().Get("ETag"))
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/geo"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Distance returns the great-circle distance and initial bearing between the
+// countries given by the from and to codes, or between their capitals with
+// by=capital.
+func (ch *CountryHandler) Distance(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+	anchor, err := geo.ParseAnchor(c.Query("by"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	leg, err := ch.cs.Distance(c.Request.Context(), from, to, anchor)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leg)
+}
+
+// CountryAt answers which country contains the lat and lng query
+// parameters.
+func (ch *CountryHandler) CountryAt(c *gin.Context) {
+	p, ok := latLng(c)
+	if !ok {
+		return
+	}
+
+	country, err := ch.cs.CountryAt(c.Request.Context(), p)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeCountry(c, country)
+}
+
+// NearestCountries lists the k countries, or capitals with by=capital,
+// closest to the lat and lng query parameters.
+func (ch *CountryHandler) NearestCountries(c *gin.Context) {
+	p, ok := latLng(c)
+	if !ok {
+		return
+	}
+	k, err := strconv.Atoi(c.DefaultQuery("k", "5"))
+	if err != nil || k < 1 || k > geo.MaxNearest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("k must be between 1 and %d", geo.MaxNearest)})
+		return
+	}
+	anchor, err := geo.ParseAnchor(c.Query("by"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	hits, err := ch.cs.NearestCountries(c.Request.Context(), p, k, anchor)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hits)
+}
+
+// latLng parses the lat and lng query parameters.
+func latLng(c *gin.Context) (models.LatLng, bool) {
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng must be numbers"})
+		return models.LatLng{}, false
+	}
+	return models.LatLng{Lat: lat, Lng: lng}, true
+}
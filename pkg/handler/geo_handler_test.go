@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/geo"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Argentina"}, "capital": ["Buenos Aires"], "capitalInfo": {"latlng": [-34.58, -58.67]}, "latlng": [-34, -64], "population": 45376763, "currencies": {"ARS": {"symbol": "$"}}, "cca2": "AR", "cca3": "ARG"},
+		{"name": {"common": "Uruguay"}, "capital": ["Montevideo"], "capitalInfo": {"latlng": [-34.85, -56.17]}, "latlng": [-33, -56], "population": 3473727, "currencies": {"UYU": {"symbol": "$"}}, "cca2": "UY", "cca3": "URY"}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/geo/distance", ch.Distance)
+	r.GET("/api/geo/nearest", ch.NearestCountries)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/geo/distance?from=AR&to=UY&by=capital", http.StatusOK, `"capital":"Montevideo"`},
+		{"/api/geo/distance?from=AR&to=XX", http.StatusNotFound, `"country not found"`},
+		{"/api/geo/distance?from=AR&to=UY&by=centroid", http.StatusBadRequest, `invalid anchor`},
+		{"/api/geo/distance?from=AR", http.StatusBadRequest, `"from and to are required"`},
+		{"/api/geo/nearest?lat=-34.9&lng=-56.2&k=1", http.StatusOK, `"cca3":"URY"`},
+		{"/api/geo/nearest?lat=-34.9&lng=-56.2&k=1", http.StatusOK, `"distanceKm"`},
+		{"/api/geo/nearest?lat=-95&lng=0", http.StatusBadRequest, `invalid coordinates`},
+		{"/api/geo/nearest?lat=north&lng=0", http.StatusBadRequest, `"lat and lng must be numbers"`},
+		{"/api/geo/nearest?lat=0&lng=0&k=100", http.StatusBadRequest, `"k must be between 1 and 25"`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+}
+
+func TestCountryAtHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[{"name": {"common": "Paraguay"}, "capital": ["Asunción"], "population": 7132530, "currencies": {"PYG": {"symbol": "₲"}}, "cca2": "PY", "cca3": "PRY"}]`
+	boundaries, err := geo.ParseBoundaries([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"ISO_A2": "PY"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[-62.6, -27.6], [-54.3, -27.6], [-54.3, -19.3], [-62.6, -19.3], [-62.6, -27.6]]
+		]}}
+	]}`))
+	assert.NoError(t, err)
+	cs, err := country.NewOfflineCountryService([]byte(snapshot), country.WithBoundaries(boundaries))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/geo/reverse", ch.CountryAt)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/geo/reverse?lat=-25.3&lng=-57.6", http.StatusOK, `{"name":"Paraguay","capital":"Asunción","currency":"₲","currencyCode":"PYG","population":7132530}`},
+		{"/api/geo/reverse?lat=0&lng=-30", http.StatusNotFound, `"country not found"`},
+		{"/api/geo/reverse?lat=-25.3", http.StatusBadRequest, `"lat and lng must be numbers"`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+
+	cs, err = country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	r = gin.New()
+	r.GET("/api/geo/reverse", NewCountryHandler(cs).CountryAt)
+	req := httptest.NewRequest(http.MethodGet, "/api/geo/reverse?lat=-25.3&lng=-57.6", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCountryByIP looks up the country of the ip query parameter. When ip is
+// omitted or "me" the caller's address is used, taken from X-Forwarded-For
+// only when the request came through a trusted proxy.
+func (ch *CountryHandler) GetCountryByIP(c *gin.Context) {
+	ip := c.Query("ip")
+	if ip == "" || ip == "me" {
+		ip = c.ClientIP()
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ip must be an IPv4 or IPv6 address"})
+		return
+	}
+
+	country, err := ch.cs.CountryByIP(c.Request.Context(), addr.WithZone(""))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	writeCountry(c, country)
+}
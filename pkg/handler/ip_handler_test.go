@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"country-search-api/pkg/service/ipdb"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCountryByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[{"name": {"common": "Ecuador"}, "capital": ["Quito"], "population": 17643060, "currencies": {"USD": {"symbol": "$"}}, "cca2": "EC", "cca3": "ECU"}]`
+	db, err := ipdb.Parse([]byte("186.0.0.0,186.0.255.255,EC\n2800:370::/32,EC\n"))
+	assert.NoError(t, err)
+	cs, err := country.NewOfflineCountryService([]byte(snapshot), country.WithIPDatabase(db))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	// httptest requests come from 192.0.2.1.
+	assert.NoError(t, r.SetTrustedProxies([]string{"192.0.2.1"}))
+	r.GET("/api/countries/by-ip", ch.GetCountryByIP)
+
+	tests := []struct {
+		url  string
+		xff  string
+		code int
+		body string
+	}{
+		{"/api/countries/by-ip?ip=186.0.1.1", "", http.StatusOK, `"name":"Ecuador"`},
+		{"/api/countries/by-ip?ip=2800:370::1&view=full", "", http.StatusOK, `"cca3":"ECU"`},
+		{"/api/countries/by-ip?ip=8.8.8.8", "", http.StatusNotFound, `"country not found"`},
+		{"/api/countries/by-ip?ip=not-an-ip", "", http.StatusBadRequest, `"ip must be an IPv4 or IPv6 address"`},
+		{"/api/countries/by-ip", "186.0.9.9", http.StatusOK, `"name":"Ecuador"`},
+		{"/api/countries/by-ip?ip=me", "186.0.9.9, 192.0.2.1", http.StatusOK, `"name":"Ecuador"`},
+		{"/api/countries/by-ip", "", http.StatusNotFound, `"country not found"`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if tt.xff != "" {
+			req.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+
+	// X-Forwarded-For from an untrusted peer is ignored.
+	assert.NoError(t, r.SetTrustedProxies(nil))
+	req := httptest.NewRequest(http.MethodGet, "/api/countries/by-ip", nil)
+	req.Header.Set("X-Forwarded-For", "186.0.9.9")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
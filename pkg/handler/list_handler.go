@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultPageSize = 25
+
+// ListCountries pages through the full dataset, optionally filtered by
+// region, subregion, lang and currency and sorted by name, population, area
+// or density. The total count is returned in X-Total-Count, the next page's
+// cursor in X-Next-Cursor and neighbouring pages in the Link header. As lang
+// is a filter here, names are localized from Accept-Language only.
+func (ch *CountryHandler) ListCountries(c *gin.Context) {
+	opts := country.ListOptions{
+		Filter: country.Filter{
+			Region:    c.Query("region"),
+			Subregion: c.Query("subregion"),
+			Language:  c.Query("lang"),
+			Currency:  c.Query("currency"),
+		},
+		Sort:   c.DefaultQuery("sort", "name"),
+		Cursor: c.Query("cursor"),
+	}
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		opts.Desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return
+	}
+	var err error
+	if opts.Limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPageSize))); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", country.MaxPageSize)})
+		return
+	}
+	if opts.Offset, err = strconv.Atoi(c.DefaultQuery("offset", "0")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+	view, ok := parseView(c, "")
+	if !ok {
+		return
+	}
+
+	page, err := ch.cs.ListCountries(c.Request.Context(), opts)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.Itoa(page.Total))
+	if page.Next != "" {
+		c.Header("X-Next-Cursor", page.Next)
+	}
+	if links := pageLinks(c, opts, page); len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+	renderCountries(c, view, page.Countries)
+}
+
+// pageLinks builds RFC 8288 links to the first, previous and next pages.
+// The next page is always linked by cursor so that following it stays
+// correct across a dataset refresh; only offset pages link backwards.
+func pageLinks(c *gin.Context, opts country.ListOptions, page country.Page) []string {
+	link := func(rel string, set map[string]string) string {
+		q := c.Request.URL.Query()
+		for _, p := range []string{"offset", "cursor"} {
+			q.Del(p)
+		}
+		for k, v := range set {
+			q.Set(k, v)
+		}
+		u := *c.Request.URL
+		u.RawQuery = q.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+	}
+
+	links := []string{link("first", nil)}
+	if opts.Cursor == "" && opts.Offset > 0 {
+		links = append(links, link("prev", map[string]string{"offset": strconv.Itoa(max(opts.Offset-opts.Limit, 0))}))
+	}
+	if page.Next != "" {
+		links = append(links, link("next", map[string]string{"cursor": page.Next}))
+	}
+	return links
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListCountriesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Peru"}, "capital": ["Lima"], "population": 32971846, "area": 1285216, "currencies": {"PEN": {"symbol": "S/ "}}, "cca3": "PER", "region": "Americas"},
+		{"name": {"common": "Chile"}, "capital": ["Santiago"], "population": 19116209, "area": 756102, "currencies": {"CLP": {"symbol": "$"}}, "cca3": "CHL", "region": "Americas"},
+		{"name": {"common": "Bolivia"}, "capital": ["Sucre"], "population": 11673029, "area": 1098581, "currencies": {"BOB": {"symbol": "Bs."}}, "cca3": "BOL", "region": "Americas"}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries", ch.ListCountries)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/countries?sort=population&order=desc&limit=1&offset=1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Chile"`)
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+	next := w.Header().Get("X-Next-Cursor")
+	assert.NotEmpty(t, next)
+	link := w.Header().Get("Link")
+	assert.Contains(t, link, `rel="first"`)
+	assert.Contains(t, link, `offset=0`)
+	assert.Contains(t, link, `rel="prev"`)
+	assert.Contains(t, link, "cursor="+next)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/countries?sort=population&order=desc&limit=1&cursor="+next, nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Bolivia"`)
+	assert.Empty(t, w.Header().Get("X-Next-Cursor"))
+	assert.NotContains(t, w.Header().Get("Link"), `rel="next"`)
+
+	for _, query := range []string{"sort=gdp", "order=up", "limit=1000", "offset=x"} {
+		req = httptest.NewRequest(http.MethodGet, "/api/countries?"+query, nil)
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestListCountriesHandler_FilterOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Latvia"}, "capital": ["Riga"], "population": 1901548, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "LVA", "region": "Europe", "subregion": "Northern Europe"},
+		{"name": {"common": "Estonia"}, "capital": ["Tallinn"], "population": 1331057, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "EST", "region": "Europe", "subregion": "Northern Europe"},
+		{"name": {"common": "Luxembourg", "official": "Grand Duchy of Luxembourg"}, "capital": ["Luxembourg"], "population": 632275, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "LUX", "region": "Europe", "subregion": "Western Europe"}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries", ch.ListCountries)
+
+	// Filter-only requests are paged like any other listing.
+	req := httptest.NewRequest(http.MethodGet, "/api/countries?subregion=Northern%20Europe", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Riga")
+	assert.Contains(t, w.Body.String(), "Tallinn")
+	assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+	assert.Contains(t, w.Header().Get("Link"), `rel="first"`)
+
+	// Without any parameter the whole dataset is listed.
+	req = httptest.NewRequest(http.MethodGet, "/api/countries", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+}
+
+func TestListCountriesHandler_LangIsFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Luxembourg", "official": "Grand Duchy of Luxembourg"}, "capital": ["Luxembourg"], "population": 632275, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "LUX", "region": "Europe", "languages": {"fra": "French", "ltz": "Luxembourgish"}, "translations": {"deu": {"common": "Luxemburg", "official": "Großherzogtum Luxemburg"}}},
+		{"name": {"common": "Liechtenstein", "official": "Principality of Liechtenstein"}, "capital": ["Vaduz"], "population": 38137, "currencies": {"CHF": {"symbol": "Fr"}}, "cca3": "LIE", "region": "Europe", "languages": {"deu": "German"}, "translations": {"deu": {"common": "Liechtenstein", "official": "Fürstentum Liechtenstein"}}}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries", ch.ListCountries)
+
+	// lang names a spoken language here, not the language of the response.
+	req := httptest.NewRequest(http.MethodGet, "/api/countries?lang=French", nil)
+	req.Header.Set("Accept-Language", "de")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+	assert.Contains(t, w.Body.String(), `"name":"Luxemburg"`)
+	assert.NotContains(t, w.Body.String(), "Liechtenstein")
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/stats"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStats aggregates every country by the dimension in the path: regions,
+// subregions or continents.
+func (ch *CountryHandler) GetStats(c *gin.Context) {
+	by, err := stats.ParseDimension(c.Param("by"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	report, err := ch.cs.Stats(c.Request.Context(), by)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// TopCountries ranks the n countries (10 by default) with the largest value
+// of field, or the smallest with order=asc, optionally within a region,
+// subregion or continent.
+func (ch *CountryHandler) TopCountries(c *gin.Context) {
+	q := stats.TopQuery{
+		Field:     c.Query("field"),
+		Region:    c.Query("region"),
+		Subregion: c.Query("subregion"),
+		Continent: c.Query("continent"),
+	}
+	if n := c.Query("n"); n != "" {
+		var err error
+		if q.N, err = strconv.Atoi(n); err != nil || q.N < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("n must be between 1 and %d", stats.MaxTop)})
+			return
+		}
+	}
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		q.Ascending = true
+	case "desc":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return
+	}
+
+	top, err := ch.cs.TopCountries(c.Request.Context(), q)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, top)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Egypt"}, "capital": ["Cairo"], "region": "Africa", "subregion": "Northern Africa", "continents": ["Africa"], "population": 102334403, "area": 1002450, "currencies": {"EGP": {"symbol": "£"}}, "borders": ["ISR", "LBY", "PSE", "SDN"], "cca2": "EG", "cca3": "EGY"},
+		{"name": {"common": "Libya"}, "capital": ["Tripoli"], "region": "Africa", "subregion": "Northern Africa", "continents": ["Africa"], "population": 6871287, "area": 1759540, "currencies": {"LYD": {"symbol": "ل.د"}}, "borders": ["DZA", "TCD", "EGY", "NER", "SDN", "TUN"], "cca2": "LY", "cca3": "LBY"},
+		{"name": {"common": "Mauritius"}, "capital": ["Port Louis"], "region": "Africa", "subregion": "Eastern Africa", "continents": ["Africa"], "population": 1265740, "area": 2040, "currencies": {"MUR": {"symbol": "₨"}}, "cca2": "MU", "cca3": "MUS"},
+		{"name": {"common": "Vanuatu"}, "capital": ["Port Vila"], "region": "Oceania", "subregion": "Melanesia", "continents": ["Oceania"], "population": 307150, "area": 12189, "currencies": {"VUV": {"symbol": "Vt"}}, "cca2": "VU", "cca3": "VUT"}
+	]`
+	cs, err := country.NewOfflineCountryService([]byte(snapshot))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/stats/top", ch.TopCountries)
+	r.GET("/api/stats/:by", ch.GetStats)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/stats/regions", http.StatusOK, `"groups":[{"name":"Africa","countries":3,"population":110471430,`},
+		{"/api/stats/regions", http.StatusOK, `"world":{"name":"World","countries":4,"population":110778580,`},
+		{"/api/stats/subregions", http.StatusOK, `"leastPopulous":{"cca3":"LBY","name":"Libya","population":6871287}`},
+		{"/api/stats/continents", http.StatusOK, `{"name":"Oceania","countries":1,`},
+		{"/api/stats/countries", http.StatusBadRequest, `"invalid statistics query: group by regions, subregions or continents"`},
+		{"/api/stats/top?field=density&n=1", http.StatusOK, `[{"rank":1,"cca3":"MUS","name":"Mauritius","value":620.4607843137255}]`},
+		{"/api/stats/top?field=borders&order=asc&region=africa&n=2", http.StatusOK, `[{"rank":1,"cca3":"MUS","name":"Mauritius","value":0},{"rank":2,"cca3":"EGY"`},
+		{"/api/stats/top?field=population&continent=Oceania", http.StatusOK, `[{"rank":1,"cca3":"VUT"`},
+		{"/api/stats/top?field=gdp", http.StatusBadRequest, `"invalid statistics query: field must be one of area, borders, density, languages, population, timezones"`},
+		{"/api/stats/top?field=area&n=0", http.StatusBadRequest, `"n must be between 1 and 250"`},
+		{"/api/stats/top?field=area&order=up", http.StatusBadRequest, `"order must be asc or desc"`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+}
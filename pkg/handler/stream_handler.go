@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"country-search-api/pkg/service/batch"
+	"country-search-api/pkg/service/country"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// streamWriteTimeout bounds how long writing a single result line may
+	// take.
+	streamWriteTimeout = 30 * time.Second
+	// maxStreamLine bounds the length of a single input line.
+	maxStreamLine = 64 << 10
+)
+
+// StreamCountries reads newline-delimited names or codes and writes one
+// NDJSON result per input line as it resolves. A line is either a plain
+// country name or a JSON item such as {"code": "JP"}. Results follow input
+// order unless ordered=false. Each line carries the status and error body
+// GetCountry would have responded with. If the body cannot be read to the
+// end, for instance because a line is longer than maxStreamLine, the results
+// so far are followed by a final line with only a status and an error.
+func (ch *CountryHandler) StreamCountries(c *gin.Context) {
+	ordered, err := strconv.ParseBool(c.DefaultQuery("ordered", "true"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ordered must be true or false"})
+		return
+	}
+	policy, err := country.ParseTieBreak(c.Query("tiebreak"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	opts, ok := parseView(c, c.Query("lang"))
+	if !ok {
+		return
+	}
+
+	// Results are written while the body is still being read.
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.EnableFullDuplex()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	items := make(chan batch.Item)
+	readErr := make(chan error, 1)
+	go func() { readErr <- readItems(ctx, c.Request.Body, items) }()
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	for res := range ch.batch.Stream(ctx, items, ordered, policy) {
+		if ctx.Err() != nil {
+			continue
+		}
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if err := enc.Encode(batchItem(res, opts)); err != nil {
+			cancel()
+			continue
+		}
+		c.Writer.Flush()
+	}
+
+	select {
+	case err := <-readErr:
+		if err != nil && ctx.Err() == nil {
+			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			_ = enc.Encode(readError(err))
+			c.Writer.Flush()
+		}
+	case <-ctx.Done():
+	}
+}
+
+// readError renders the final line reporting why the body could not be read
+// to the end.
+func readError(err error) gin.H {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return gin.H{"status": http.StatusRequestEntityTooLarge, "error": fmt.Sprintf("input line longer than %d bytes", maxStreamLine)}
+	}
+	return gin.H{"status": http.StatusBadRequest, "error": "unable to read request body"}
+}
+
+// readItems sends one item per non-blank line of body, closing items at the
+// end of the body or once ctx is done. It returns the error that stopped
+// reading before the end of the body, if any.
+func readItems(ctx context.Context, body io.Reader, items chan<- batch.Item) error {
+	defer close(items)
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 4096), maxStreamLine)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		item := batch.Item{Name: line}
+		if strings.HasPrefix(line, "{") {
+			// A malformed item is sent empty and resolves to ErrInvalidItem.
+			item = batch.Item{}
+			if err := json.Unmarshal([]byte(line), &item); err != nil {
+				item = batch.Item{}
+			}
+		}
+
+		select {
+		case items <- item:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
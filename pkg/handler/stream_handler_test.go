@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bufio"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/service/country"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStreamCountriesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/name/Bahrain?fullText=true").
+		Return([]byte(`[{"name": {"common": "Bahrain"}, "capital": ["Manama"], "population": 1701583, "currencies": {"BHD": {"symbol": ".د.ب"}}, "cca3": "BHR"}]`), nil).Once()
+	mockClient.On("Get", mock.Anything, "/alpha?codes=QAT").
+		Return([]byte(`[{"name": {"common": "Qatar"}, "capital": ["Doha"], "population": 2881060, "currencies": {"QAR": {"symbol": "ر.ق"}}, "cca2": "QA", "cca3": "QAT"}]`), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.POST("/api/countries/stream", ch.StreamCountries)
+
+	body := "Bahrain\n\n{\"code\": \"QAT\"}\n{\"code\": \n"
+	req := httptest.NewRequest(http.MethodPost, "/api/countries/stream", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "Bahrain", lines[0]["country"].(map[string]any)["name"])
+	assert.Equal(t, "Qatar", lines[1]["country"].(map[string]any)["name"])
+	assert.Equal(t, float64(http.StatusBadRequest), lines[2]["status"])
+	mockClient.AssertExpectations(t)
+}
+
+func TestStreamCountriesHandler_InvalidOrdered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ch := NewCountryHandler(country.NewCountryService(new(mock_http_client.MockClientInf), ""))
+
+	r := gin.New()
+	r.POST("/api/countries/stream", ch.StreamCountries)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/countries/stream?ordered=maybe", strings.NewReader("Bahrain\n"))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStreamCountriesHandler_OversizedLine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/name/Oman?fullText=true").
+		Return([]byte(`[{"name": {"common": "Oman"}, "capital": ["Muscat"], "population": 5106622, "currencies": {"OMR": {"symbol": "ر.ع."}}, "cca3": "OMN"}]`), nil).Once()
+
+	ch := NewCountryHandler(country.NewCountryService(mockClient, ""))
+
+	r := gin.New()
+	r.POST("/api/countries/stream", ch.StreamCountries)
+
+	body := "Oman\n" + strings.Repeat("x", maxStreamLine+1) + "\nYemen\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/countries/stream", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var lines []map[string]any
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "Oman", lines[0]["country"].(map[string]any)["name"])
+	assert.Equal(t, float64(http.StatusRequestEntityTooLarge), lines[1]["status"])
+	assert.Contains(t, lines[1]["error"], "input line longer than")
+	assert.NotContains(t, lines[1], "index")
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/timezone"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLocalTime returns the current local time in the capital and each
+// timezone of the country given by code.
+func (ch *CountryHandler) GetLocalTime(c *gin.Context) {
+	local, err := ch.cs.LocalTime(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, local)
+}
+
+// BusinessOverlap lists when, over the coming week, business hours in the
+// capitals of the from and to countries overlap. start and end default to
+// 09:00 and 17:00 local time.
+func (ch *CountryHandler) BusinessOverlap(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+	hours, err := timezone.ParseBusinessHours(c.Query("start"), c.Query("end"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	overlap, err := ch.cs.BusinessOverlap(c.Request.Context(), from, to, hours)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, overlap)
+}
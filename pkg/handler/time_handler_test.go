@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/country"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	snapshot := `[
+		{"name": {"common": "Singapore"}, "capital": ["Singapore"], "capitalInfo": {"latlng": [1.28, 103.85]}, "population": 5685807, "currencies": {"SGD": {"symbol": "$"}}, "timezones": ["UTC+08:00"], "cca2": "SG", "cca3": "SGP"},
+		{"name": {"common": "Ireland"}, "capital": ["Dublin"], "capitalInfo": {"latlng": [53.32, -6.23]}, "population": 4994724, "currencies": {"EUR": {"symbol": "€"}}, "timezones": ["UTC"], "cca2": "IE", "cca3": "IRL"},
+		{"name": {"common": "Heard Island and McDonald Islands"}, "capital": ["None"], "population": 1, "currencies": {"AUD": {"symbol": "$"}}, "cca2": "HM", "cca3": "HMD"}
+	]`
+	now := time.Date(2026, time.July, 6, 6, 0, 0, 0, time.UTC)
+	cs, err := country.NewOfflineCountryService([]byte(snapshot), country.WithClock(func() time.Time { return now }))
+	assert.NoError(t, err)
+	ch := NewCountryHandler(cs)
+
+	r := gin.New()
+	r.GET("/api/countries/:code/time", ch.GetLocalTime)
+	r.GET("/api/time/overlap", ch.BusinessOverlap)
+
+	tests := []struct {
+		url  string
+		code int
+		body string
+	}{
+		{"/api/countries/SGP/time", http.StatusOK, `"time":"2026-07-06T14:00:00+08:00"`},
+		{"/api/countries/ie/time", http.StatusOK, `"abbreviation":"IST","dst":true`},
+		{"/api/countries/XXX/time", http.StatusNotFound, `"country not found"`},
+		// Singapore works 01:00-09:00 UTC and Dublin, on summer time, 08:00-16:00 UTC.
+		{"/api/time/overlap?from=SG&to=IE", http.StatusOK, `{"start":"2026-07-06T08:00:00Z","end":"2026-07-06T09:00:00Z","minutes":60}`},
+		{"/api/time/overlap?from=SG&to=IE&start=08:00&end=18:00", http.StatusOK, `"businessHours":"08:00-18:00"`},
+		{"/api/time/overlap?from=SG&to=IE&start=19:00", http.StatusBadRequest, `"invalid business hours`},
+		{"/api/time/overlap?from=SG", http.StatusBadRequest, `"from and to are required"`},
+		{"/api/time/overlap?from=SG&to=HM", http.StatusUnprocessableEntity, `"country has no known timezone`},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.url)
+		assert.Contains(t, w.Body.String(), tt.body, tt.url)
+	}
+}
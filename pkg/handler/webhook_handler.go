@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/webhook"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	registry   *webhook.Registry
+	dispatcher *webhook.Dispatcher
+}
+
+func NewWebhookHandler(registry *webhook.Registry, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{registry: registry, dispatcher: dispatcher}
+}
+
+// CreateWebhook registers a subscription. The response is the only place the
+// signing secret is returned, so callers that let it be generated must keep it.
+func (wh *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var sub webhook.Subscription
+	if err := c.ShouldBindJSON(&sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a JSON webhook subscription"})
+		return
+	}
+
+	sub, err := wh.registry.Add(sub)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// ListWebhooks returns the subscriptions without their secrets.
+func (wh *WebhookHandler) ListWebhooks(c *gin.Context) {
+	subs := wh.registry.List()
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+func (wh *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	err := wh.registry.Remove(c.Param("id"))
+	if errors.Is(err, webhook.ErrUnknownSubscription) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeadLetters returns the events that could not be delivered.
+func (wh *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	c.JSON(http.StatusOK, wh.dispatcher.DeadLetters())
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"country-search-api/pkg/service/webhook"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := webhook.NewRegistry()
+	wh := NewWebhookHandler(registry, webhook.NewDispatcher(registry, http.DefaultClient))
+
+	r := gin.New()
+	r.POST("/api/webhooks", wh.CreateWebhook)
+	r.GET("/api/webhooks", wh.ListWebhooks)
+	r.DELETE("/api/webhooks/:id", wh.DeleteWebhook)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(`{"url": "https://example.com/hook", "countries": ["IDN"]}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var created webhook.Subscription
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Secret)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), created.ID)
+	assert.NotContains(t, w.Body.String(), created.Secret)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(`{"url": "not a url"}`))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		req = httptest.NewRequest(http.MethodDelete, "/api/webhooks/"+created.ID, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code)
+	}
+}
+
+func TestWebhookHandler_RequiresAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := webhook.NewRegistry()
+	wh := NewWebhookHandler(registry, webhook.NewDispatcher(registry, http.DefaultClient))
+
+	r := gin.New()
+	r.GET("/api/webhooks", RequireAdminToken("s3cret"), wh.ListWebhooks)
+	r.GET("/api/webhooks/dead-letters", RequireAdminToken(""), wh.ListDeadLetters)
+
+	for header, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/webhooks", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, header)
+	}
+
+	// Without a configured token the routes stay closed.
+	req := httptest.NewRequest(http.MethodGet, "/api/webhooks/dead-letters", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestWebhookHandler_RejectsPrivateDestination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := webhook.NewRegistry()
+	wh := NewWebhookHandler(registry, webhook.NewDispatcher(registry, http.DefaultClient))
+
+	r := gin.New()
+	r.POST("/api/webhooks", wh.CreateWebhook)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks", strings.NewReader(`{"url": "http://169.254.169.254/latest/meta-data"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "not a public address")
+	assert.Empty(t, registry.List())
+}
@@ -21,9 +21,10 @@ func Init(level slog.Level) {
 	})
 }
 
+// Log returns the logger set up by Init, initializing it at info level on
+// first use. Going through once.Do every time keeps concurrent first calls
+// from racing on the logger.
 func Log() *slog.Logger {
-	if log == nil {
-		Init(slog.LevelInfo)
-	}
+	Init(slog.LevelInfo)
 	return log
 }
@@ -1,10 +1,15 @@
 package models
 
 type Country struct {
-	Name       string `json:"name"`
-	Capital    string `json:"capital"`
-	Currency   string `json:"currency"`
-	Population int64  `json:"population"`
+	Name    string `json:"name"`
+	Capital string `json:"capital"`
+	// Currency is the symbol of the first currency, which is ambiguous
+	// ("$"); CurrencyCode is its ISO 4217 code.
+	Currency     string `json:"currency"`
+	CurrencyCode string `json:"currencyCode,omitempty"`
+	Population   int64  `json:"population"`
+	// ResolvedFrom is set when the query was an alias or a fuzzy match.
+	ResolvedFrom *Resolution `json:"resolvedFrom,omitempty"`
 }
 
 func (c Country) Validate() bool {
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// CountryDetails is the complete record for a country. Country is the
+// summary view of it that the search endpoint has always returned.
+type CountryDetails struct {
+	Name         CountryName `json:"name"`
+	CCA2         string      `json:"cca2"`
+	CCA3         string      `json:"cca3"`
+	CCN3         string      `json:"ccn3,omitempty"`
+	CIOC         string      `json:"cioc,omitempty"`
+	AltSpellings []string    `json:"altSpellings,omitempty"`
+	// Translations maps an ISO 639-3 language code to the name in that language.
+	Translations  map[string]NativeName `json:"translations,omitempty"`
+	Capitals      []string              `json:"capitals"`
+	CapitalLatLng *LatLng               `json:"capitalLatLng,omitempty"`
+	Currencies    []Currency            `json:"currencies"`
+	Languages     []Language            `json:"languages"`
+	Region        string                `json:"region"`
+	Subregion     string                `json:"subregion,omitempty"`
+	Continents    []string              `json:"continents"`
+	Borders       []string              `json:"borders"`
+	Area          float64               `json:"area"`
+	LatLng        *LatLng               `json:"latlng,omitempty"`
+	Landlocked    bool                  `json:"landlocked"`
+	Population    int64                 `json:"population"`
+	Timezones     []string              `json:"timezones"`
+	CallingCodes  []string              `json:"callingCodes"`
+	TLDs          []string              `json:"tlds"`
+	Flag          Flag                  `json:"flag"`
+	CarSide       string                `json:"carSide,omitempty"`
+	UNMember      bool                  `json:"unMember"`
+	// ResolvedFrom is set on lookup results when the query was an alias or
+	// a fuzzy match rather than one of the country's names.
+	ResolvedFrom *Resolution `json:"resolvedFrom,omitempty"`
+}
+
+// Resolution describes how a query was mapped onto a canonical country.
+type Resolution struct {
+	Query string `json:"query"`
+	// Via is "alias", "translation" or "fuzzy".
+	Via  string `json:"via"`
+	Name string `json:"name"`
+	CCA3 string `json:"cca3"`
+}
+
+type CountryName struct {
+	Common   string `json:"common"`
+	Official string `json:"official"`
+	// Native maps an ISO 639-3 language code to the name in that language.
+	Native map[string]NativeName `json:"native,omitempty"`
+}
+
+type NativeName struct {
+	Common   string `json:"common"`
+	Official string `json:"official"`
+}
+
+// Currency is an ISO 4217 currency.
+type Currency struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	// MinorUnits is the number of decimal places amounts are given in, such
+	// as 2 for INR and 0 for JPY.
+	MinorUnits int `json:"minorUnits"`
+}
+
+type Language struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type Flag struct {
+	Emoji string `json:"emoji"`
+	PNG   string `json:"png,omitempty"`
+	SVG   string `json:"svg,omitempty"`
+	Alt   string `json:"alt,omitempty"`
+}
+
+// Codes returns the ISO 3166-1 alpha-2, alpha-3 and numeric codes that are set.
+func (d CountryDetails) Codes() []string {
+	codes := make([]string, 0, 3)
+	for _, code := range []string{d.CCA2, d.CCA3, d.CCN3} {
+		if code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Summary returns the compact view with the first capital and currency.
+func (d CountryDetails) Summary() Country {
+	c := Country{
+		Name:         d.Name.Common,
+		Population:   d.Population,
+		ResolvedFrom: d.ResolvedFrom,
+	}
+	if len(d.Capitals) > 0 {
+		c.Capital = d.Capitals[0]
+	}
+	if len(d.Currencies) > 0 {
+		c.Currency = d.Currencies[0].Symbol
+		c.CurrencyCode = d.Currencies[0].Code
+	}
+	return c
+}
+
+// Density returns people per square kilometre, or zero without an area.
+func (d CountryDetails) Density() float64 {
+	if d.Area <= 0 {
+		return 0
+	}
+	return float64(d.Population) / d.Area
+}
+
+// Validate reports whether d has everything the summary view requires.
+func (d CountryDetails) Validate() bool {
+	return d.Summary().Validate()
+}
+
+// detailFields lists the JSON field names of CountryDetails other than
+// lookup metadata.
+var detailFields = slices.DeleteFunc(jsonFieldNames(reflect.TypeOf(CountryDetails{})), func(name string) bool {
+	return name == "resolvedFrom"
+})
+
+// DetailFields returns the JSON field names a client may project onto.
+func DetailFields() []string {
+	return append([]string(nil), detailFields...)
+}
+
+// IsDetailField reports whether name is a JSON field of CountryDetails.
+func IsDetailField(name string) bool {
+	return slices.Contains(detailFields, name)
+}
+
+// Project returns the JSON object of d restricted to fields. Fields that are
+// empty and omitted from the full record are omitted here too.
+func (d CountryDetails) Project(fields []string) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			projected[f] = v
+		}
+	}
+	return projected, nil
+}
+
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
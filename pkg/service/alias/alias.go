@@ -0,0 +1,168 @@
+package alias
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/fuzzy"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Table maps common names and abbreviations such as "USA", "Holland" or
+// "Burma", and names in other languages such as "Allemagne", onto an ISO
+// 3166-1 code. It is immutable once built.
+type Table struct {
+	codes map[string]entry
+}
+
+// Kind tells what a table entry was built from.
+type Kind string
+
+const (
+	// KindAlias is an alternate spelling or a configured alias.
+	KindAlias Kind = "alias"
+	// KindTranslation is a country's name in another language.
+	KindTranslation Kind = "translation"
+)
+
+type entry struct {
+	code string
+	kind Kind
+}
+
+// NewTable builds a table from the alternate spellings and translated names
+// in countries plus overrides, which map an alias to a code and take
+// precedence. Spellings shared by several countries, equal to a country's
+// own name, or contained as whole words in another country's name, like
+// "Congo" in "DR Congo", are left out so that they never shadow a real name
+// lookup or hide an ambiguous one.
+func NewTable(countries []models.CountryDetails, overrides map[string]string) *Table {
+	t := &Table{codes: make(map[string]entry)}
+
+	names := make(map[string]bool)
+	idx := newNameIndex(countries)
+	for _, c := range countries {
+		names[fuzzy.Normalize(c.Name.Common)] = true
+		names[fuzzy.Normalize(c.Name.Official)] = true
+	}
+
+	ambiguous := make(map[string]bool)
+	for _, c := range countries {
+		for _, s := range spellings(c) {
+			key := fuzzy.Normalize(s.text)
+			if key == "" || names[key] || ambiguous[key] {
+				continue
+			}
+			if idx.namesOther(key, c.CCA3) {
+				ambiguous[key] = true
+				continue
+			}
+			if e, ok := t.codes[key]; ok {
+				// An alternate spelling listed first wins over the same
+				// translated name of the same country.
+				if e.code != c.CCA3 {
+					delete(t.codes, key)
+					ambiguous[key] = true
+				}
+				continue
+			}
+			t.codes[key] = entry{code: c.CCA3, kind: s.kind}
+		}
+	}
+
+	for a, code := range overrides {
+		if key := fuzzy.Normalize(a); key != "" {
+			t.codes[key] = entry{code: code, kind: KindAlias}
+		}
+	}
+	return t
+}
+
+// nameIndex finds the countries whose common or official name contains a
+// spelling as whole words.
+type nameIndex struct {
+	countries []models.CountryDetails
+	names     [][2]string
+	byWord    map[string][]int
+}
+
+func newNameIndex(countries []models.CountryDetails) *nameIndex {
+	idx := &nameIndex{countries: countries, names: make([][2]string, len(countries)), byWord: make(map[string][]int)}
+	for i, c := range countries {
+		idx.names[i] = [2]string{fuzzy.Normalize(c.Name.Common), fuzzy.Normalize(c.Name.Official)}
+		seen := make(map[string]bool)
+		for _, name := range idx.names[i] {
+			for _, w := range fuzzy.Words(name) {
+				if !seen[w] {
+					seen[w] = true
+					idx.byWord[w] = append(idx.byWord[w], i)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// namesOther reports whether the name of a country other than cca3 contains
+// key as whole words.
+func (idx *nameIndex) namesOther(key, cca3 string) bool {
+	words := fuzzy.Words(key)
+	if len(words) == 0 {
+		return false
+	}
+	for _, i := range idx.byWord[words[0]] {
+		if idx.countries[i].CCA3 == cca3 {
+			continue
+		}
+		if fuzzy.ContainsWords(idx.names[i][0], key) || fuzzy.ContainsWords(idx.names[i][1], key) {
+			return true
+		}
+	}
+	return false
+}
+
+type spelling struct {
+	text string
+	kind Kind
+}
+
+// spellings lists the alternate spellings of c followed by its common and
+// official name in every language it has a translation for.
+func spellings(c models.CountryDetails) []spelling {
+	var out []spelling
+	for _, s := range c.AltSpellings {
+		out = append(out, spelling{s, KindAlias})
+	}
+	for _, t := range c.Translations {
+		out = append(out, spelling{t.Common, KindTranslation}, spelling{t.Official, KindTranslation})
+	}
+	return out
+}
+
+// Lookup returns the code name is an alias for.
+func (t *Table) Lookup(name string) (string, bool) {
+	code, _, ok := t.Resolve(name)
+	return code, ok
+}
+
+// Resolve returns the code name is an alias for together with the kind of
+// spelling it matched.
+func (t *Table) Resolve(name string) (string, Kind, bool) {
+	e, ok := t.codes[fuzzy.Normalize(name)]
+	return e.code, e.kind, ok
+}
+
+// LoadFile reads aliases from a JSON object mapping each alias to an ISO
+// 3166-1 code, for example {"Holland": "NLD"}.
+func LoadFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases map[string]string
+	if err := json.Unmarshal(b, &aliases); err != nil {
+		return nil, fmt.Errorf("unable to parse aliases from %s: %w", path, err)
+	}
+	return aliases, nil
+}
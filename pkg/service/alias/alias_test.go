@@ -0,0 +1,110 @@
+package alias
+
+import (
+	"country-search-api/pkg/models"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var countries = []models.CountryDetails{
+	{Name: models.CountryName{Common: "United States", Official: "United States of America"}, CCA3: "USA", AltSpellings: []string{"US", "USA", "United States of America"}},
+	{Name: models.CountryName{Common: "Netherlands", Official: "Kingdom of the Netherlands"}, CCA3: "NLD", AltSpellings: []string{"NL", "Holland", "Nederland"},
+		Translations: map[string]models.NativeName{"fra": {Common: "Pays-Bas", Official: "Royaume des Pays-Bas"}}},
+	{Name: models.CountryName{Common: "Republic of the Congo", Official: "Republic of the Congo"}, CCA3: "COG", AltSpellings: []string{"CG", "Congo-Brazzaville"},
+		Translations: map[string]models.NativeName{"ita": {Common: "Congo", Official: "Repubblica del Congo"}}},
+	{Name: models.CountryName{Common: "DR Congo", Official: "Democratic Republic of the Congo"}, CCA3: "COD", AltSpellings: []string{"CD", "DR Congo", "Congo-Kinshasa", "DRC"}},
+}
+
+func TestTable_Lookup(t *testing.T) {
+	table := NewTable(countries, map[string]string{"Amerika": "USA"})
+
+	code, ok := table.Lookup("usa")
+	assert.True(t, ok)
+	assert.Equal(t, "USA", code)
+
+	code, ok = table.Lookup(" holland ")
+	assert.True(t, ok)
+	assert.Equal(t, "NLD", code)
+
+	code, ok = table.Lookup("Amerika")
+	assert.True(t, ok)
+	assert.Equal(t, "USA", code)
+
+	code, ok = table.Lookup("pays-bas")
+	assert.True(t, ok)
+	assert.Equal(t, "NLD", code)
+
+	code, ok = table.Lookup("Royaume des Pays-Bas")
+	assert.True(t, ok)
+	assert.Equal(t, "NLD", code)
+
+	code, ok = table.Lookup("Congo-Brazzaville")
+	assert.True(t, ok)
+	assert.Equal(t, "COG", code)
+
+	code, ok = table.Lookup("Repubblica del Congo")
+	assert.True(t, ok)
+	assert.Equal(t, "COG", code)
+
+	// A translation that is part of the other Congo's name is not an alias
+	// for either.
+	_, ok = table.Lookup("Congo")
+	assert.False(t, ok)
+
+	// A country's own name is left to the regular name lookup.
+	_, ok = table.Lookup("United States of America")
+	assert.False(t, ok)
+}
+
+func TestTable_ResolveKind(t *testing.T) {
+	table := NewTable(countries, map[string]string{"Amerika": "USA"})
+
+	for name, want := range map[string]Kind{
+		"Holland":              KindAlias,
+		"Amerika":              KindAlias,
+		"Pays-Bas":             KindTranslation,
+		"Repubblica del Congo": KindTranslation,
+	} {
+		_, kind, ok := table.Resolve(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, kind, name)
+	}
+}
+
+func TestTable_OverridesWithoutDataset(t *testing.T) {
+	table := NewTable(nil, map[string]string{"Burma": "MMR"})
+
+	code, ok := table.Lookup("burma")
+	assert.True(t, ok)
+	assert.Equal(t, "MMR", code)
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"Holland": "NLD", "Ivory Coast": "CIV"}`), 0o600))
+
+	aliases, err := LoadFile(path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Holland": "NLD", "Ivory Coast": "CIV"}, aliases)
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestTable_SharedSpelling(t *testing.T) {
+	table := NewTable([]models.CountryDetails{
+		{Name: models.CountryName{Common: "Niger", Official: "Republic of Niger"}, CCA3: "NER", AltSpellings: []string{"NE", "Nijar"}},
+		{Name: models.CountryName{Common: "Nigeria", Official: "Federal Republic of Nigeria"}, CCA3: "NGA", AltSpellings: []string{"NG", "Nijar"}},
+	}, nil)
+
+	_, ok := table.Lookup("Nijar")
+	assert.False(t, ok)
+
+	code, ok := table.Lookup("ng")
+	assert.True(t, ok)
+	assert.Equal(t, "NGA", code)
+}
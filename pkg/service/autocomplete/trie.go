@@ -0,0 +1,182 @@
+package autocomplete
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/fuzzy"
+	"sort"
+	"strings"
+)
+
+// MaxLimit is the most suggestions a single query may return. Each trie node
+// keeps this many candidates so lookups never walk a subtree.
+const MaxLimit = 25
+
+// Suggestion is a country whose name, or one of its words, starts with the
+// query.
+type Suggestion struct {
+	Name    string `json:"name"`
+	CCA2    string `json:"cca2"`
+	CCA3    string `json:"cca3"`
+	Flag    string `json:"flag,omitempty"`
+	Matched string `json:"matched"`
+}
+
+type candidate struct {
+	country int
+	term    string
+}
+
+type node struct {
+	children   map[rune]*node
+	candidates []candidate
+}
+
+// Trie is a prefix index over every known name of a set of countries. It is
+// immutable once built and safe for concurrent use.
+type Trie struct {
+	countries []models.CountryDetails
+	root      *node
+	// exact maps a normalized name to the countries that carry it, so an
+	// exact hit can outrank more populous prefix matches.
+	exact map[string][]candidate
+}
+
+func NewTrie(countries []models.CountryDetails) *Trie {
+	t := &Trie{
+		countries: countries,
+		root:      &node{},
+		exact:     make(map[string][]candidate),
+	}
+
+	for i, c := range countries {
+		for _, name := range names(c) {
+			n := fuzzy.Normalize(name)
+			if n == "" {
+				continue
+			}
+			if !hasCountry(t.exact[n], i) {
+				t.exact[n] = append(t.exact[n], candidate{country: i, term: name})
+			}
+			// Index every word start so "kor" finds "South Korea".
+			for start := 0; start < len(n); {
+				t.insert(n[start:], candidate{country: i, term: name})
+				next := strings.IndexByte(n[start:], ' ')
+				if next < 0 {
+					break
+				}
+				start += next + 1
+			}
+		}
+	}
+
+	t.rank(t.root)
+	return t
+}
+
+// names lists the names of c in the order they are preferred for display.
+func names(c models.CountryDetails) []string {
+	list := []string{c.Name.Common, c.Name.Official}
+	list = append(list, c.AltSpellings...)
+	for _, lang := range sortedKeys(c.Name.Native) {
+		list = append(list, c.Name.Native[lang].Common, c.Name.Native[lang].Official)
+	}
+	for _, lang := range sortedKeys(c.Translations) {
+		list = append(list, c.Translations[lang].Common, c.Translations[lang].Official)
+	}
+	return list
+}
+
+func (t *Trie) insert(key string, cand candidate) {
+	n := t.root
+	for _, r := range key {
+		child, ok := n.children[r]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			child = &node{}
+			n.children[r] = child
+		}
+		n = child
+		// Countries are inserted one at a time, so a repeat can only be the
+		// most recent candidate.
+		if last := len(n.candidates) - 1; last < 0 || n.candidates[last].country != cand.country {
+			n.candidates = append(n.candidates, cand)
+		}
+	}
+}
+
+// rank orders every node's candidates by population and keeps the top
+// MaxLimit.
+func (t *Trie) rank(n *node) {
+	sort.SliceStable(n.candidates, func(i, j int) bool {
+		return t.countries[n.candidates[i].country].Population > t.countries[n.candidates[j].country].Population
+	})
+	if len(n.candidates) > MaxLimit {
+		n.candidates = n.candidates[:MaxLimit:MaxLimit]
+	}
+	for _, child := range n.children {
+		t.rank(child)
+	}
+}
+
+// Suggest returns up to limit countries for prefix: exact name matches
+// first, then the most populous countries with a name or word starting with
+// prefix.
+func (t *Trie) Suggest(prefix string, limit int) []Suggestion {
+	prefix = fuzzy.Normalize(prefix)
+	limit = min(limit, MaxLimit)
+	if prefix == "" || limit <= 0 {
+		return nil
+	}
+
+	n := t.root
+	for _, r := range prefix {
+		if n = n.children[r]; n == nil {
+			return []Suggestion{}
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, limit)
+	seen := make(map[int]bool, limit)
+	add := func(c candidate) {
+		if len(suggestions) == limit || seen[c.country] {
+			return
+		}
+		seen[c.country] = true
+		country := t.countries[c.country]
+		suggestions = append(suggestions, Suggestion{
+			Name:    country.Name.Common,
+			CCA2:    country.CCA2,
+			CCA3:    country.CCA3,
+			Flag:    country.Flag.Emoji,
+			Matched: c.term,
+		})
+	}
+
+	for _, c := range t.exact[prefix] {
+		add(c)
+	}
+	for _, c := range n.candidates {
+		add(c)
+	}
+	return suggestions
+}
+
+func hasCountry(candidates []candidate, country int) bool {
+	for _, c := range candidates {
+		if c.country == country {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
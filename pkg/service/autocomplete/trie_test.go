@@ -0,0 +1,74 @@
+package autocomplete
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var countries = []models.CountryDetails{
+	{Name: models.CountryName{Common: "Nigeria", Official: "Federal Republic of Nigeria"}, CCA2: "NG", CCA3: "NGA", Population: 206139587},
+	{Name: models.CountryName{Common: "Niger", Official: "Republic of Niger"}, CCA2: "NE", CCA3: "NER", Population: 24206636},
+	{Name: models.CountryName{Common: "South Korea", Official: "Republic of Korea"}, CCA2: "KR", CCA3: "KOR", Population: 51780579},
+	{Name: models.CountryName{Common: "North Korea", Official: "Democratic People's Republic of Korea"}, CCA2: "KP", CCA3: "PRK", Population: 25778815},
+	{
+		Name: models.CountryName{
+			Common:   "Germany",
+			Official: "Federal Republic of Germany",
+			Native:   map[string]models.NativeName{"deu": {Common: "Deutschland", Official: "Bundesrepublik Deutschland"}},
+		},
+		CCA2: "DE", CCA3: "DEU", Population: 83240525, AltSpellings: []string{"DE"},
+	},
+	{Name: models.CountryName{Common: "Côte d'Ivoire"}, CCA2: "CI", CCA3: "CIV", Population: 26378275, AltSpellings: []string{"Ivory Coast"}},
+}
+
+func suggestedNames(t *Trie, q string, limit int) []string {
+	names := []string{}
+	for _, s := range t.Suggest(q, limit) {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestTrie_PrefixRankedByPopulation(t *testing.T) {
+	trie := NewTrie(countries)
+
+	assert.Equal(t, []string{"Nigeria", "Niger"}, suggestedNames(trie, "nig", 10))
+	assert.Equal(t, []string{"Nigeria"}, suggestedNames(trie, "nig", 1))
+}
+
+func TestTrie_ExactMatchFirst(t *testing.T) {
+	trie := NewTrie(countries)
+
+	assert.Equal(t, []string{"Niger", "Nigeria"}, suggestedNames(trie, "Niger", 10))
+}
+
+func TestTrie_WordStartsAndAlternateNames(t *testing.T) {
+	trie := NewTrie(countries)
+
+	assert.Equal(t, []string{"South Korea", "North Korea"}, suggestedNames(trie, "kor", 10))
+
+	suggestions := trie.Suggest("deutsch", 10)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "DEU", suggestions[0].CCA3)
+	assert.Equal(t, "Deutschland", suggestions[0].Matched)
+
+	assert.Equal(t, []string{"Côte d'Ivoire"}, suggestedNames(trie, "ivory", 10))
+	assert.Equal(t, []string{"Côte d'Ivoire"}, suggestedNames(trie, "cote", 10))
+}
+
+func TestTrie_NoMatch(t *testing.T) {
+	trie := NewTrie(countries)
+
+	assert.Empty(t, trie.Suggest("atl", 10))
+	assert.Empty(t, trie.Suggest("", 10))
+}
+
+func BenchmarkTrie_Suggest(b *testing.B) {
+	trie := NewTrie(countries)
+
+	for b.Loop() {
+		trie.Suggest("no", 10)
+	}
+}
@@ -0,0 +1,118 @@
+package batch
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/country"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxItems bounds how many items a single batch accepts.
+	MaxItems = 500
+	// DefaultWorkers is how many lookups a Resolver runs at once by default.
+	DefaultWorkers = 8
+)
+
+var ErrInvalidItem = errors.New("invalid batch item")
+
+// Item asks for one country by name or by ISO 3166-1 code.
+type Item struct {
+	Name string `json:"name,omitempty"`
+	Code string `json:"code,omitempty"`
+}
+
+// Result is the outcome of one item. Exactly one of Country and Err is set.
+type Result struct {
+	Index   int
+	Item    Item
+	Country *models.CountryDetails
+	Err     error
+}
+
+// Resolver looks up items through a CountryService with bounded concurrency.
+// Identical lookups running at the same time, within a batch or across
+// batches, share a single call.
+type Resolver struct {
+	cs      country.CountryService
+	workers int
+	flight  flight
+}
+
+func NewResolver(cs country.CountryService, workers int) *Resolver {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Resolver{cs: cs, workers: workers}
+}
+
+// Resolve looks up every item and returns the results in item order. A
+// failing item only fails its own result; once ctx is done the remaining
+// items fail with its error.
+func (r *Resolver) Resolve(ctx context.Context, items []Item, policy country.TieBreak) []Result {
+	results := make([]Result, len(items))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(r.workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = r.ResolveOne(ctx, i, items[i], policy)
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(items); next++ {
+		select {
+		case indexes <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := next; i < len(items); i++ {
+		results[i] = Result{Index: i, Item: items[i], Err: ctx.Err()}
+	}
+	return results
+}
+
+// ResolveOne looks up a single item.
+func (r *Resolver) ResolveOne(ctx context.Context, index int, item Item, policy country.TieBreak) Result {
+	result := Result{Index: index, Item: item}
+	if err := ctx.Err(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	var c models.CountryDetails
+	var err error
+	name, code := strings.TrimSpace(item.Name), strings.TrimSpace(item.Code)
+	switch {
+	case name != "" && code != "", name == "" && code == "":
+		err = fmt.Errorf("%w: give exactly one of name and code", ErrInvalidItem)
+	case code != "":
+		c, err = r.flight.do(ctx, "code:"+strings.ToUpper(code), func(ctx context.Context) (models.CountryDetails, error) {
+			return r.cs.GetCountryByCode(ctx, code)
+		})
+	default:
+		c, err = r.flight.do(ctx, "name:"+string(policy)+":"+strings.ToLower(name), func(ctx context.Context) (models.CountryDetails, error) {
+			return r.cs.GetCountryDetailsByNameTieBreak(ctx, name, policy)
+		})
+	}
+
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Country = &c
+	return result
+}
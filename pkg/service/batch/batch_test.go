@@ -0,0 +1,114 @@
+package batch
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/country"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const tongaBody = `[{"name": {"common": "Tonga"}, "capital": ["Nuku'alofa"], "population": 105697, "currencies": {"TOP": {"symbol": "T$"}}, "cca2": "TO", "cca3": "TON", "ccn3": "776"}]`
+
+func TestResolver_PerItemResults(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/name/Tonga?fullText=true").Return([]byte(tongaBody), nil).Once()
+	mockClient.On("Get", mock.Anything, "/alpha?codes=TUV").Return([]byte(`[{"name": {"common": "Tuvalu"}, "capital": ["Funafuti"], "population": 11792, "currencies": {"AUD": {"symbol": "$"}}, "cca2": "TV", "cca3": "TUV"}]`), nil).Once()
+	mockClient.On("Get", mock.Anything, "/name/Lemuria?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(func(endpoint string) bool {
+		return strings.HasPrefix(endpoint, "/all?")
+	})).Return(nil, http_client.ErrUpstream)
+	r := NewResolver(country.NewCountryService(mockClient, ""), 2)
+
+	results := r.Resolve(context.Background(), []Item{
+		{Name: "Tonga"},
+		{Code: "tuv"},
+		{Name: "Lemuria"},
+		{Name: "Tonga", Code: "TO"},
+		{Code: "T0"},
+	}, country.TieBreakNone)
+
+	assert.Len(t, results, 5)
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+	}
+	assert.Equal(t, "TON", results[0].Country.CCA3)
+	assert.Equal(t, "TUV", results[1].Country.CCA3)
+	assert.ErrorIs(t, results[2].Err, http_client.ErrNotFound)
+	assert.ErrorIs(t, results[3].Err, ErrInvalidItem)
+	assert.ErrorIs(t, results[4].Err, country.ErrInvalidCode)
+	assert.Nil(t, results[4].Country)
+}
+
+func TestResolver_DeduplicatesInFlightLookups(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/name/Kiribati?fullText=true").
+		After(50*time.Millisecond).
+		Return([]byte(`[{"name": {"common": "Kiribati"}, "capital": ["South Tarawa"], "population": 119446, "currencies": {"AUD": {"symbol": "$"}}, "cca3": "KIR"}]`), nil).
+		Once()
+	r := NewResolver(country.NewCountryService(mockClient, ""), DefaultWorkers)
+
+	items := []Item{{Name: "Kiribati"}, {Name: "kiribati"}, {Name: "KIRIBATI "}, {Name: "Kiribati"}}
+	results := r.Resolve(context.Background(), items, country.TieBreakNone)
+
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		assert.Equal(t, "KIR", result.Country.CCA3)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func TestResolver_CancelledContext(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	r := NewResolver(country.NewCountryService(mockClient, ""), 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := r.Resolve(ctx, []Item{{Code: "ABW"}, {Code: "AFG"}, {Code: "AGO"}}, country.TieBreakNone)
+
+	assert.Len(t, results, 3)
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+	mockClient.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestResolver_LeaderCancelDoesNotFailWaiters(t *testing.T) {
+	release := make(chan struct{})
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "/alpha?codes=WLF").Return(func(ctx context.Context, _ string) ([]byte, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return []byte(`[{"name": {"common": "Wallis and Futuna"}, "capital": ["Mata-Utu"], "population": 11750, "currencies": {"XPF": {"symbol": "₣"}}, "cca2": "WF", "cca3": "WLF"}]`), nil
+	}).Once()
+	r := NewResolver(country.NewCountryService(mockClient, ""), 1)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := make(chan Result)
+	go func() { leader <- r.ResolveOne(leaderCtx, 0, Item{Code: "WLF"}, country.TieBreakNone) }()
+	assert.Eventually(t, func() bool {
+		r.flight.mu.Lock()
+		defer r.flight.mu.Unlock()
+		return len(r.flight.calls) == 1
+	}, time.Second, time.Millisecond)
+
+	waiter := make(chan Result)
+	go func() { waiter <- r.ResolveOne(context.Background(), 1, Item{Code: "wlf"}, country.TieBreakNone) }()
+
+	cancelLeader()
+	assert.ErrorIs(t, (<-leader).Err, context.Canceled)
+
+	close(release)
+	result := <-waiter
+	assert.NoError(t, result.Err)
+	assert.Equal(t, "WLF", result.Country.CCA3)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,62 @@
+package batch
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"sync"
+	"time"
+)
+
+// callTimeout bounds a shared lookup, which no longer follows the deadline
+// of the caller that started it.
+const callTimeout = 20 * time.Second
+
+// flight collapses concurrent lookups of the same key into one call.
+type flight struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done    chan struct{}
+	country models.CountryDetails
+	err     error
+}
+
+// do runs fn for key unless a call for key is already running, in which
+// case it waits for that call and shares its result. The call runs detached
+// from the cancellation of whichever caller started it, so one caller giving
+// up neither fails the others nor aborts the call; each caller stops waiting
+// when its own ctx is done.
+func (f *flight) do(ctx context.Context, key string, fn func(context.Context) (models.CountryDetails, error)) (models.CountryDetails, error) {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[string]*call)
+	}
+	c, ok := f.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		f.calls[key] = c
+		go f.run(context.WithoutCancel(ctx), key, c, fn)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.country, c.err
+	case <-ctx.Done():
+		return models.CountryDetails{}, ctx.Err()
+	}
+}
+
+func (f *flight) run(ctx context.Context, key string, c *call, fn func(context.Context) (models.CountryDetails, error)) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	c.country, c.err = fn(ctx)
+
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	close(c.done)
+}
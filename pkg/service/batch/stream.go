@@ -0,0 +1,72 @@
+package batch
+
+import (
+	"context"
+	"country-search-api/pkg/service/country"
+	"sync"
+)
+
+// Stream resolves items as they arrive and sends the results on the
+// returned channel, which is closed once items is closed and every result
+// has been sent. With ordered set, results follow input order; otherwise
+// they are sent as soon as they resolve.
+//
+// At most the Resolver's worker count of items are in flight or waiting to
+// be sent, so memory stays bounded and a slow reader of the results stops
+// Stream from reading further items. If ctx is done, remaining results are
+// discarded; items must still be closed for Stream to finish.
+func (r *Resolver) Stream(ctx context.Context, items <-chan Item, ordered bool, policy country.TieBreak) <-chan Result {
+	out := make(chan Result)
+	resolved := make(chan Result)
+	window := make(chan struct{}, r.workers)
+
+	go func() {
+		var wg sync.WaitGroup
+		index := 0
+		for item := range items {
+			window <- struct{}{}
+			wg.Add(1)
+			go func(i int, item Item) {
+				defer wg.Done()
+				resolved <- r.ResolveOne(ctx, i, item, policy)
+			}(index, item)
+			index++
+		}
+		wg.Wait()
+		close(resolved)
+	}()
+
+	go func() {
+		defer close(out)
+
+		send := func(res Result) {
+			select {
+			case out <- res:
+			case <-ctx.Done():
+			}
+			<-window
+		}
+
+		pending := make(map[int]Result)
+		next := 0
+		for res := range resolved {
+			if !ordered {
+				send(res)
+				continue
+			}
+
+			pending[res.Index] = res
+			for {
+				res, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				send(res)
+				next++
+			}
+		}
+	}()
+
+	return out
+}
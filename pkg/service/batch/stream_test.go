@@ -0,0 +1,84 @@
+package batch
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/service/country"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// slowIslands answers each name after its delay in milliseconds. Tests use
+// distinct names since results are kept in the shared cache.
+func slowIslands(delays map[string]time.Duration) *mock_http_client.MockClientInf {
+	mockClient := new(mock_http_client.MockClientInf)
+	for name, delay := range delays {
+		body := `[{"name": {"common": "` + name + `"}, "capital": ["X"], "population": 1, "currencies": {"XXX": {"symbol": "X"}}}]`
+		mockClient.On("Get", mock.Anything, "/name/"+name+"?fullText=true").
+			After(delay*time.Millisecond).
+			Return([]byte(body), nil).
+			Maybe()
+	}
+	return mockClient
+}
+
+func streamNames(r *Resolver, ordered bool, names ...string) []string {
+	items := make(chan Item)
+	go func() {
+		defer close(items)
+		for _, n := range names {
+			items <- Item{Name: n}
+		}
+	}()
+
+	var got []string
+	for res := range r.Stream(context.Background(), items, ordered, country.TieBreakNone) {
+		got = append(got, res.Country.Name.Common)
+	}
+	return got
+}
+
+func TestStream_Ordered(t *testing.T) {
+	islands := slowIslands(map[string]time.Duration{"Palau": 60, "Vanuatu": 30, "Tokelau": 0})
+	r := NewResolver(country.NewCountryService(islands, ""), 3)
+
+	got := streamNames(r, true, "Palau", "Vanuatu", "Tokelau")
+
+	assert.Equal(t, []string{"Palau", "Vanuatu", "Tokelau"}, got)
+}
+
+func TestStream_Unordered(t *testing.T) {
+	islands := slowIslands(map[string]time.Duration{"Niue": 60, "Pitcairn": 30, "Nauru": 0})
+	r := NewResolver(country.NewCountryService(islands, ""), 3)
+
+	got := streamNames(r, false, "Niue", "Pitcairn", "Nauru")
+
+	assert.Equal(t, []string{"Nauru", "Pitcairn", "Niue"}, got)
+}
+
+func TestStream_Backpressure(t *testing.T) {
+	r := NewResolver(country.NewCountryService(new(mock_http_client.MockClientInf), ""), 2)
+
+	items := make(chan Item, 10)
+	for range 10 {
+		items <- Item{}
+	}
+	close(items)
+
+	results := r.Stream(context.Background(), items, false, country.TieBreakNone)
+
+	// Nobody reads the results, so only the two items in flight and the one
+	// waiting for a slot are taken from the input.
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, items, 7)
+
+	n := 0
+	for res := range results {
+		assert.ErrorIs(t, res.Err, ErrInvalidItem)
+		n++
+	}
+	assert.Equal(t, 10, n)
+}
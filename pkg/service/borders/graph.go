@@ -0,0 +1,222 @@
+package borders
+
+import (
+	"country-search-api/pkg/models"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// MaxHops bounds how far Neighbors walks from a country.
+const MaxHops = 10
+
+var (
+	ErrUnknownCountry = errors.New("country not in border graph")
+	ErrNoRoute        = errors.New("no overland route")
+)
+
+// Member is a country in a route, neighborhood or component.
+type Member struct {
+	CCA3 string `json:"cca3"`
+	Name string `json:"name"`
+}
+
+// Neighbor is a country reachable overland in Hops border crossings.
+type Neighbor struct {
+	Member
+	Hops int `json:"hops"`
+}
+
+// Component is a set of countries connected by land borders. A component of
+// one country is an island or a country without land borders.
+type Component struct {
+	Size      int      `json:"size"`
+	Island    bool     `json:"island"`
+	Countries []Member `json:"countries"`
+}
+
+// Graph is an undirected graph of countries joined by land borders. It is
+// immutable once built and safe for concurrent use.
+type Graph struct {
+	members map[string]Member
+	// codes maps every code of a country onto its alpha-3 code.
+	codes map[string]string
+	adj   map[string][]string
+}
+
+func NewGraph(countries []models.CountryDetails) *Graph {
+	g := &Graph{
+		members: make(map[string]Member, len(countries)),
+		codes:   make(map[string]string, 3*len(countries)),
+		adj:     make(map[string][]string, len(countries)),
+	}
+
+	for _, c := range countries {
+		g.members[c.CCA3] = Member{CCA3: c.CCA3, Name: c.Name.Common}
+		for _, code := range c.Codes() {
+			g.codes[code] = c.CCA3
+		}
+	}
+
+	// Borders are listed on both sides upstream, but add each edge both ways
+	// so a one-sided listing still connects the pair.
+	edges := make(map[string]map[string]bool, len(countries))
+	link := func(a, b string) {
+		if edges[a] == nil {
+			edges[a] = make(map[string]bool)
+		}
+		edges[a][b] = true
+	}
+	for _, c := range countries {
+		for _, b := range c.Borders {
+			b = strings.ToUpper(b)
+			if _, ok := g.members[b]; !ok || b == c.CCA3 {
+				continue
+			}
+			link(c.CCA3, b)
+			link(b, c.CCA3)
+		}
+	}
+	for code, set := range edges {
+		list := make([]string, 0, len(set))
+		for n := range set {
+			list = append(list, n)
+		}
+		sort.Strings(list)
+		g.adj[code] = list
+	}
+
+	return g
+}
+
+// Resolve returns the alpha-3 code of the country with the given alpha-2,
+// alpha-3 or numeric code.
+func (g *Graph) Resolve(code string) (string, error) {
+	cca3, ok := g.codes[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return "", ErrUnknownCountry
+	}
+	return cca3, nil
+}
+
+// Path returns a shortest overland route from one country to another,
+// both ends included. Ties are broken by alpha-3 code so the same route is
+// returned every time.
+func (g *Graph) Path(from, to string) ([]Member, error) {
+	start, err := g.Resolve(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := g.Resolve(to)
+	if err != nil {
+		return nil, err
+	}
+
+	prev := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 && !hasKey(prev, end) {
+		current := queue[0]
+		queue = queue[1:]
+		for _, n := range g.adj[current] {
+			if hasKey(prev, n) {
+				continue
+			}
+			prev[n] = current
+			queue = append(queue, n)
+		}
+	}
+	if !hasKey(prev, end) {
+		return nil, ErrNoRoute
+	}
+
+	var path []Member
+	for code := end; code != ""; code = prev[code] {
+		path = append(path, g.members[code])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// Neighbors returns every country at most k border crossings from code,
+// nearest first and by name within the same distance.
+func (g *Graph) Neighbors(code string, k int) ([]Neighbor, error) {
+	start, err := g.Resolve(code)
+	if err != nil {
+		return nil, err
+	}
+
+	hops := map[string]int{start: 0}
+	frontier := []string{start}
+	neighbors := []Neighbor{}
+	for depth := 1; depth <= k && len(frontier) > 0; depth++ {
+		var next []string
+		for _, current := range frontier {
+			for _, n := range g.adj[current] {
+				if _, seen := hops[n]; seen {
+					continue
+				}
+				hops[n] = depth
+				next = append(next, n)
+			}
+		}
+
+		ring := make([]Neighbor, 0, len(next))
+		for _, n := range next {
+			ring = append(ring, Neighbor{Member: g.members[n], Hops: depth})
+		}
+		sort.Slice(ring, func(i, j int) bool { return ring[i].Name < ring[j].Name })
+		neighbors = append(neighbors, ring...)
+		frontier = next
+	}
+	return neighbors, nil
+}
+
+// Components splits the graph into sets of countries connected by land,
+// largest first. Countries within a component are sorted by name.
+func (g *Graph) Components() []Component {
+	codes := make([]string, 0, len(g.members))
+	for code := range g.members {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	seen := make(map[string]bool, len(codes))
+	var components []Component
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+
+		var members []Member
+		seen[code] = true
+		queue := []string{code}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			members = append(members, g.members[current])
+			for _, n := range g.adj[current] {
+				if !seen[n] {
+					seen[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+
+		sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+		components = append(components, Component{
+			Size:      len(members),
+			Island:    len(members) == 1,
+			Countries: members,
+		})
+	}
+
+	sort.SliceStable(components, func(i, j int) bool { return components[i].Size > components[j].Size })
+	return components
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
@@ -0,0 +1,90 @@
+package borders
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func country(cca2, cca3, name string, borders ...string) models.CountryDetails {
+	return models.CountryDetails{Name: models.CountryName{Common: name}, CCA2: cca2, CCA3: cca3, Borders: borders}
+}
+
+var countries = []models.CountryDetails{
+	country("PT", "PRT", "Portugal", "ESP"),
+	country("ES", "ESP", "Spain", "AND", "FRA", "PRT"),
+	country("AD", "AND", "Andorra", "ESP", "FRA"),
+	country("FR", "FRA", "France", "AND", "BEL", "DEU", "ESP"),
+	country("BE", "BEL", "Belgium", "FRA", "DEU"),
+	// Germany omits Belgium; the edge still counts from Belgium's side.
+	country("DE", "DEU", "Germany", "FRA", "POL"),
+	country("PL", "POL", "Poland", "DEU"),
+	country("IS", "ISL", "Iceland"),
+	country("IE", "IRL", "Ireland", "GBR"),
+	country("GB", "GBR", "United Kingdom", "IRL"),
+}
+
+func memberNames[T interface{ name() string }](list []T) []string {
+	names := []string{}
+	for _, m := range list {
+		names = append(names, m.name())
+	}
+	return names
+}
+
+func (m Member) name() string   { return m.Name }
+func (n Neighbor) name() string { return n.Name }
+
+func TestGraph_Path(t *testing.T) {
+	g := NewGraph(countries)
+
+	path, err := g.Path("PRT", "pl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Portugal", "Spain", "France", "Germany", "Poland"}, memberNames(path))
+
+	path, err = g.Path("BE", "DEU")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Belgium", "Germany"}, memberNames(path))
+
+	path, err = g.Path("ISL", "ISL")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Iceland"}, memberNames(path))
+
+	_, err = g.Path("PRT", "IRL")
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	_, err = g.Path("PRT", "XYZ")
+	assert.ErrorIs(t, err, ErrUnknownCountry)
+}
+
+func TestGraph_Neighbors(t *testing.T) {
+	g := NewGraph(countries)
+
+	neighbors, err := g.Neighbors("ESP", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Andorra", "France", "Portugal"}, memberNames(neighbors))
+
+	neighbors, err = g.Neighbors("ESP", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Andorra", "France", "Portugal", "Belgium", "Germany"}, memberNames(neighbors))
+	assert.Equal(t, 2, neighbors[4].Hops)
+
+	neighbors, err = g.Neighbors("ISL", 3)
+	assert.NoError(t, err)
+	assert.Empty(t, neighbors)
+
+	_, err = g.Neighbors("XX", 1)
+	assert.ErrorIs(t, err, ErrUnknownCountry)
+}
+
+func TestGraph_Components(t *testing.T) {
+	components := NewGraph(countries).Components()
+
+	assert.Len(t, components, 3)
+	assert.Equal(t, 7, components[0].Size)
+	assert.False(t, components[0].Island)
+	assert.Equal(t, []string{"Ireland", "United Kingdom"}, memberNames(components[1].Countries))
+	assert.Equal(t, []string{"Iceland"}, memberNames(components[2].Countries))
+	assert.True(t, components[2].Island)
+}
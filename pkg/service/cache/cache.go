@@ -7,6 +7,7 @@ import (
 type CacheInf interface {
 	Get(key string) (any, bool)
 	Set(key string, value any)
+	Clear()
 }
 
 type cache struct {
@@ -41,3 +42,10 @@ func (c *cache) Set(key string, value any) {
 	defer c.mu.Unlock()
 	c.data[key] = value
 }
+
+// Clear removes every entry.
+func (c *cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.data)
+}
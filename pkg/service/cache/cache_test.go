@@ -125,3 +125,16 @@ func BenchmarkCache_GetSet(b *testing.B) {
 // 		t.Fatalf("expected NewDelhi, got %v", val)
 // 	}
 // }
+
+func TestCache_Clear(t *testing.T) {
+	c := NewCache()
+	c.Set("country", "India")
+	c.Set("region:asia", "India")
+
+	c.Clear()
+
+	_, ok := c.Get("country")
+	assert.False(t, ok)
+	_, ok = c.Get("region:asia")
+	assert.False(t, ok)
+}
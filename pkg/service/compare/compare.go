@@ -0,0 +1,182 @@
+package compare
+
+import (
+	"country-search-api/pkg/models"
+	"slices"
+	"sort"
+)
+
+const (
+	// MinCountries and MaxCountries bound how many countries one comparison
+	// holds.
+	MinCountries = 2
+	MaxCountries = 10
+)
+
+// Metrics holds one value per compared quantity.
+type Metrics[T any] struct {
+	Population T `json:"population"`
+	Area       T `json:"area"`
+	Density    T `json:"density"`
+}
+
+// Entry is one country of a comparison, with its fields aligned with the
+// others.
+type Entry struct {
+	CCA3       string            `json:"cca3"`
+	Name       string            `json:"name"`
+	Population int64             `json:"population"`
+	Area       float64           `json:"area"`
+	Density    float64           `json:"density"`
+	Languages  []models.Language `json:"languages"`
+	Currencies []models.Currency `json:"currencies"`
+	// Ratios divides each quantity by that of the first country, or is zero
+	// where the first country's is zero.
+	Ratios Metrics[float64] `json:"ratios"`
+	// Ranks places the country within the set, 1 being the largest. Equal
+	// values share a rank.
+	Ranks Metrics[int] `json:"ranks"`
+}
+
+// Border is a land border between two of the compared countries.
+type Border struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SharedLanguage is a language spoken in at least two of the compared
+// countries.
+type SharedLanguage struct {
+	models.Language
+	Countries []string `json:"countries"`
+	// All reports whether every compared country speaks it.
+	All bool `json:"all"`
+}
+
+// Comparison lines up countries side by side.
+type Comparison struct {
+	Countries       []Entry          `json:"countries"`
+	SharedBorders   []Border         `json:"sharedBorders"`
+	SharedLanguages []SharedLanguage `json:"sharedLanguages"`
+}
+
+// Countries compares countries in the order given; ratios are taken
+// against the first.
+func Countries(countries []models.CountryDetails) Comparison {
+	cmp := Comparison{
+		Countries:       make([]Entry, len(countries)),
+		SharedBorders:   sharedBorders(countries),
+		SharedLanguages: sharedLanguages(countries),
+	}
+
+	for i, c := range countries {
+		cmp.Countries[i] = Entry{
+			CCA3:       c.CCA3,
+			Name:       c.Name.Common,
+			Population: c.Population,
+			Area:       c.Area,
+			Density:    c.Density(),
+			Languages:  c.Languages,
+			Currencies: c.Currencies,
+		}
+	}
+
+	population := column(cmp.Countries, func(e Entry) float64 { return float64(e.Population) })
+	area := column(cmp.Countries, func(e Entry) float64 { return e.Area })
+	density := column(cmp.Countries, func(e Entry) float64 { return e.Density })
+	populationRanks, areaRanks, densityRanks := rank(population), rank(area), rank(density)
+
+	for i := range cmp.Countries {
+		cmp.Countries[i].Ratios = Metrics[float64]{
+			Population: ratio(population, i),
+			Area:       ratio(area, i),
+			Density:    ratio(density, i),
+		}
+		cmp.Countries[i].Ranks = Metrics[int]{
+			Population: populationRanks[i],
+			Area:       areaRanks[i],
+			Density:    densityRanks[i],
+		}
+	}
+	return cmp
+}
+
+func column(entries []Entry, value func(Entry) float64) []float64 {
+	values := make([]float64, len(entries))
+	for i, e := range entries {
+		values[i] = value(e)
+	}
+	return values
+}
+
+// ratio divides values[i] by values[0], or returns zero when that is zero.
+func ratio(values []float64, i int) float64 {
+	if values[0] == 0 {
+		return 0
+	}
+	return values[i] / values[0]
+}
+
+// rank returns the standard competition rank ("1224") of each value,
+// largest first.
+func rank(values []float64) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] > values[order[b]] })
+
+	ranks := make([]int, len(values))
+	for pos, i := range order {
+		if pos > 0 && values[i] == values[order[pos-1]] {
+			ranks[i] = ranks[order[pos-1]]
+			continue
+		}
+		ranks[i] = pos + 1
+	}
+	return ranks
+}
+
+// sharedBorders lists each pair of compared countries that border each
+// other, in the order they were given.
+func sharedBorders(countries []models.CountryDetails) []Border {
+	borders := []Border{}
+	for i, a := range countries {
+		for _, b := range countries[i+1:] {
+			if slices.Contains(a.Borders, b.CCA3) || slices.Contains(b.Borders, a.CCA3) {
+				borders = append(borders, Border{From: a.CCA3, To: b.CCA3})
+			}
+		}
+	}
+	return borders
+}
+
+// sharedLanguages lists the languages spoken in two or more of countries,
+// the most widely shared first.
+func sharedLanguages(countries []models.CountryDetails) []SharedLanguage {
+	byCode := map[string]*SharedLanguage{}
+	var order []string
+	for _, c := range countries {
+		for _, l := range c.Languages {
+			shared, ok := byCode[l.Code]
+			if !ok {
+				shared = &SharedLanguage{Language: l}
+				byCode[l.Code] = shared
+				order = append(order, l.Code)
+			}
+			if !slices.Contains(shared.Countries, c.CCA3) {
+				shared.Countries = append(shared.Countries, c.CCA3)
+			}
+		}
+	}
+
+	languages := []SharedLanguage{}
+	for _, code := range order {
+		if shared := byCode[code]; len(shared.Countries) >= 2 {
+			shared.All = len(shared.Countries) == len(countries)
+			languages = append(languages, *shared)
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return len(languages[i].Countries) > len(languages[j].Countries) })
+	return languages
+}
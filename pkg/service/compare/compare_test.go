@@ -0,0 +1,67 @@
+package compare
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	hindi   = models.Language{Code: "hin", Name: "Hindi"}
+	english = models.Language{Code: "eng", Name: "English"}
+	nepali  = models.Language{Code: "nep", Name: "Nepali"}
+
+	india = models.CountryDetails{
+		Name: models.CountryName{Common: "India"}, CCA3: "IND", Population: 1380004385, Area: 3287590,
+		Languages: []models.Language{english, hindi}, Borders: []string{"BGD", "BTN", "MMR", "CHN", "NPL", "PAK"},
+	}
+	china = models.CountryDetails{
+		Name: models.CountryName{Common: "China"}, CCA3: "CHN", Population: 1402112000, Area: 9706961,
+		Languages: []models.Language{{Code: "zho", Name: "Chinese"}}, Borders: []string{"IND", "NPL"},
+	}
+	nepal = models.CountryDetails{
+		Name: models.CountryName{Common: "Nepal"}, CCA3: "NPL", Population: 29136808, Area: 147181,
+		Languages: []models.Language{english, nepali}, Borders: []string{"CHN", "IND"},
+	}
+)
+
+func TestCountries(t *testing.T) {
+	cmp := Countries([]models.CountryDetails{india, china, nepal})
+
+	assert.Len(t, cmp.Countries, 3)
+	in, cn, np := cmp.Countries[0], cmp.Countries[1], cmp.Countries[2]
+	assert.Equal(t, "IND", in.CCA3)
+	assert.InDelta(t, 419.76, in.Density, 0.01)
+
+	assert.Equal(t, Metrics[float64]{Population: 1, Area: 1, Density: 1}, in.Ratios)
+	assert.InDelta(t, 1.016, cn.Ratios.Population, 0.001)
+	assert.InDelta(t, 2.953, cn.Ratios.Area, 0.001)
+	assert.InDelta(t, 0.0211, np.Ratios.Population, 0.0001)
+
+	assert.Equal(t, Metrics[int]{Population: 2, Area: 2, Density: 1}, in.Ranks)
+	assert.Equal(t, Metrics[int]{Population: 1, Area: 1, Density: 3}, cn.Ranks)
+	assert.Equal(t, Metrics[int]{Population: 3, Area: 3, Density: 2}, np.Ranks)
+
+	assert.Equal(t, []Border{{From: "IND", To: "CHN"}, {From: "IND", To: "NPL"}, {From: "CHN", To: "NPL"}}, cmp.SharedBorders)
+	assert.Equal(t, []SharedLanguage{{Language: english, Countries: []string{"IND", "NPL"}}}, cmp.SharedLanguages)
+}
+
+func TestCountries_AllShareLanguageAndMissingArea(t *testing.T) {
+	nowhere := models.CountryDetails{CCA3: "XXA", Population: 100, Languages: []models.Language{english}}
+	somewhere := models.CountryDetails{CCA3: "XXB", Population: 100, Area: 10, Languages: []models.Language{english, english}}
+
+	cmp := Countries([]models.CountryDetails{nowhere, somewhere})
+
+	assert.Equal(t, Metrics[float64]{Population: 1}, cmp.Countries[1].Ratios)
+	// Equal populations share first place.
+	assert.Equal(t, Metrics[int]{Population: 1, Area: 2, Density: 2}, cmp.Countries[0].Ranks)
+	assert.Equal(t, Metrics[int]{Population: 1, Area: 1, Density: 1}, cmp.Countries[1].Ranks)
+	assert.Empty(t, cmp.SharedBorders)
+	assert.Equal(t, []SharedLanguage{{Language: english, Countries: []string{"XXA", "XXB"}, All: true}}, cmp.SharedLanguages)
+}
+
+func TestRank(t *testing.T) {
+	assert.Equal(t, []int{2, 1, 2, 4}, rank([]float64{5, 9, 5, 1}))
+	assert.Empty(t, rank(nil))
+}
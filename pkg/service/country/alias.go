@@ -0,0 +1,117 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/alias"
+	http_client "country-search-api/pkg/service/client"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Option configures a CountryService.
+type Option func(*countryService)
+
+// WithAliases adds aliases, each mapping a common name such as "Holland" to
+// an ISO 3166-1 code, on top of the upstream alternate spellings.
+func WithAliases(aliases map[string]string) Option {
+	return func(cs *countryService) {
+		cs.aliases = aliases
+	}
+}
+
+// NormalizeAliases checks that every alias maps onto a well-formed ISO
+// 3166-1 code and returns the aliases with their codes normalized, so that a
+// typo in an alias file fails at startup rather than on the first query.
+func NormalizeAliases(aliases map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(aliases))
+	for _, a := range slices.Sorted(maps.Keys(aliases)) {
+		code, err := NormalizeCode(aliases[a])
+		if err != nil {
+			return nil, fmt.Errorf("alias %q: %w", a, err)
+		}
+		normalized[a] = code
+	}
+	return normalized, nil
+}
+
+// aliasCode returns the code name is an alias for, and whether it is an
+// alternate spelling or a translated name. Upstream spellings are only
+// consulted once the full dataset is in memory, so an alias check never
+// triggers a dataset load on its own.
+func (cs *countryService) aliasCode(name string) (string, alias.Kind, bool) {
+	if table, ok := cs.aliasTable.Peek(); ok {
+		return table.Resolve(name)
+	}
+	return cs.configAliases.Resolve(name)
+}
+
+// lookupAlias resolves name through the alias table. ok is false when name
+// is not an alias.
+func (cs *countryService) lookupAlias(ctx context.Context, name string) (country models.CountryDetails, ok bool, err error) {
+	code, kind, ok := cs.aliasCode(name)
+	if !ok {
+		return models.CountryDetails{}, false, nil
+	}
+
+	logger.Log().Info("resolving country alias:", "alias", name, "code", code, "kind", kind)
+	country, found := cs.datasetByCode(code)
+	if !found {
+		country, err = cs.GetCountryByCode(ctx, code)
+		if err != nil {
+			return models.CountryDetails{}, true, err
+		}
+	}
+	if !country.Validate() {
+		return models.CountryDetails{}, true, http_client.ErrInvalidData
+	}
+
+	country.ResolvedFrom = &models.Resolution{
+		Query: name,
+		Via:   string(kind),
+		Name:  country.Name.Common,
+		CCA3:  country.CCA3,
+	}
+	return country, true, nil
+}
+
+func (cs *countryService) newAliasTable(countries []models.CountryDetails) *alias.Table {
+	return alias.NewTable(countries, cs.aliases)
+}
+
+// datasetByCode returns the country with code from the in-memory dataset, if
+// it has been loaded.
+func (cs *countryService) datasetByCode(code string) (models.CountryDetails, bool) {
+	byCode, ok := cs.codes.Peek()
+	if !ok {
+		return models.CountryDetails{}, false
+	}
+	country, ok := byCode[strings.ToUpper(code)]
+	return country, ok
+}
+
+// countryByCode looks code up in the full dataset, loading it if needed.
+func (cs *countryService) countryByCode(ctx context.Context, code string) (models.CountryDetails, error) {
+	byCode, err := cs.codes.Get(ctx)
+	if err != nil {
+		return models.CountryDetails{}, err
+	}
+	country, ok := byCode[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return models.CountryDetails{}, fmt.Errorf("%w: %q", http_client.ErrNotFound, code)
+	}
+	return country, nil
+}
+
+func indexByCode(countries []models.CountryDetails) map[string]models.CountryDetails {
+	byCode := make(map[string]models.CountryDetails, 3*len(countries))
+	for _, c := range countries {
+		for _, code := range c.Codes() {
+			byCode[code] = c
+		}
+	}
+	return byCode
+}
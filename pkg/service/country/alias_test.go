@@ -0,0 +1,80 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCountryDetailsByName_ConfiguredAlias(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[{"name": {"common": "Myanmar"}, "capital": ["Naypyidaw"], "population": 54409794, "currencies": {"MMK": {"symbol": "Ks"}}, "cca2": "MM", "cca3": "MMR", "ccn3": "104"}]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/alpha?codes=MMR").Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL", WithAliases(map[string]string{"Burma": "MMR"}))
+
+	country, err := ncs.GetCountryDetailsByName(context.Background(), "burma")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Myanmar", country.Name.Common)
+	assert.Equal(t, &models.Resolution{Query: "burma", Via: "alias", Name: "Myanmar", CCA3: "MMR"}, country.ResolvedFrom)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryDetailsByName_UpstreamAltSpelling(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	all := `[{"name": {"common": "Netherlands", "official": "Kingdom of the Netherlands"}, "capital": ["Amsterdam"], "population": 16655799, "currencies": {"EUR": {"symbol": "€"}}, "cca2": "NL", "cca3": "NLD", "ccn3": "528", "altSpellings": ["NL", "Holland", "Nederland"]}]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Holland?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(all), nil).Times(3)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	country, err := ncs.GetCountryDetailsByName(context.Background(), "Holland")
+	assert.NoError(t, err)
+	assert.Equal(t, "Netherlands", country.Name.Common)
+	assert.Equal(t, "alias", country.ResolvedFrom.Via)
+
+	// With the dataset loaded the alias is resolved before any upstream call.
+	summary, err := ncs.GetCountryByName(context.Background(), "holland")
+	assert.NoError(t, err)
+	assert.Equal(t, "Amsterdam", summary.Capital)
+	assert.Equal(t, "Netherlands", summary.ResolvedFrom.Name)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryDetailsByName_TranslatedName(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	all := `[{"name": {"common": "Finland", "official": "Republic of Finland"}, "capital": ["Helsinki"], "population": 5530719, "currencies": {"EUR": {"symbol": "€"}}, "cca2": "FI", "cca3": "FIN", "ccn3": "246", "translations": {"deu": {"common": "Finnland", "official": "Republik Finnland"}}}]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Finnland?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(all), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	country, err := ncs.GetCountryDetailsByName(context.Background(), "Finnland")
+	assert.NoError(t, err)
+	assert.Equal(t, "Finland", country.Name.Common)
+	assert.Equal(t, &models.Resolution{Query: "Finnland", Via: "translation", Name: "Finland", CCA3: "FIN"}, country.ResolvedFrom)
+
+	summary, err := ncs.GetCountryByName(context.Background(), "republik finnland")
+	assert.NoError(t, err)
+	assert.Equal(t, "Helsinki", summary.Capital)
+}
+
+func TestNormalizeAliases(t *testing.T) {
+	aliases, err := NormalizeAliases(map[string]string{"Burma": " mmr ", "Ivory Coast": "384"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Burma": "MMR", "Ivory Coast": "384"}, aliases)
+
+	_, err = NormalizeAliases(map[string]string{"Burma": "MMR", "Holland": "NLDD"})
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	assert.ErrorContains(t, err, `alias "Holland"`)
+}
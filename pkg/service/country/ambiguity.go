@@ -0,0 +1,113 @@
+package country
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/fuzzy"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrAmbiguous       = errors.New("ambiguous country name")
+	ErrInvalidTieBreak = errors.New("invalid tie-break policy")
+)
+
+// TieBreak chooses one country when a name matches several.
+type TieBreak string
+
+const (
+	// TieBreakNone reports every candidate instead of choosing.
+	TieBreakNone TieBreak = ""
+	// TieBreakPopulous chooses the most populous candidate.
+	TieBreakPopulous TieBreak = "populous"
+	// TieBreakExact chooses the candidate whose common name is the query,
+	// and reports every candidate when there is none.
+	TieBreakExact TieBreak = "exact"
+	// TieBreakFirst chooses the best ranked candidate.
+	TieBreakFirst TieBreak = "first"
+)
+
+// ParseTieBreak validates a tie-break policy name.
+func ParseTieBreak(s string) (TieBreak, error) {
+	switch t := TieBreak(s); t {
+	case TieBreakNone, TieBreakPopulous, TieBreakExact, TieBreakFirst:
+		return t, nil
+	default:
+		return TieBreakNone, fmt.Errorf("%w: %q", ErrInvalidTieBreak, s)
+	}
+}
+
+// AmbiguousError reports a name that matches several countries. It unwraps
+// to ErrAmbiguous.
+type AmbiguousError struct {
+	Query      string
+	Candidates []models.CountryDetails
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("country name %q matches %d countries", e.Query, len(e.Candidates))
+}
+
+func (e *AmbiguousError) Unwrap() error {
+	return ErrAmbiguous
+}
+
+// choose returns the single candidate picked by policy, or an AmbiguousError
+// when policy cannot decide. candidates must be ranked best first.
+func choose(query string, candidates []models.CountryDetails, policy TieBreak) (models.CountryDetails, error) {
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	switch policy {
+	case TieBreakFirst:
+		return candidates[0], nil
+	case TieBreakPopulous:
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if c.Population > best.Population {
+				best = c
+			}
+		}
+		return best, nil
+	case TieBreakExact:
+		for _, c := range candidates {
+			if strings.EqualFold(c.Name.Common, strings.TrimSpace(query)) {
+				return c, nil
+			}
+		}
+	}
+	return models.CountryDetails{}, &AmbiguousError{Query: query, Candidates: candidates}
+}
+
+// wordMatches returns the countries whose common or official name contains
+// query as whole words, such as "Korea" in "South Korea" and "North Korea",
+// ranked like partial search results and then by population.
+func wordMatches(query string, countries []models.CountryDetails) []models.CountryDetails {
+	q := fuzzy.Normalize(query)
+	if q == "" {
+		return nil
+	}
+
+	var matches []match
+	for _, c := range countries {
+		if fuzzy.ContainsWords(fuzzy.Normalize(c.Name.Common), q) || fuzzy.ContainsWords(fuzzy.Normalize(c.Name.Official), q) {
+			matches = append(matches, match{country: c, rank: rankMatch(query, c.Name.Common, c.Name.Official)})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].country.Population > matches[j].country.Population
+	})
+
+	ranked := make([]models.CountryDetails, 0, len(matches))
+	for _, m := range matches {
+		ranked = append(ranked, m.country)
+	}
+	return ranked
+}
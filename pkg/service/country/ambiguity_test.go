@@ -0,0 +1,120 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const koreasBody = `[
+	{"name": {"common": "North Korea", "official": "Democratic People's Republic of Korea"}, "capital": ["Pyongyang"], "population": 25778815, "currencies": {"KPW": {"symbol": "₩"}}, "cca2": "KP", "cca3": "PRK"},
+	{"name": {"common": "South Korea", "official": "Republic of Korea"}, "capital": ["Seoul"], "population": 51780579, "currencies": {"KRW": {"symbol": "₩"}}, "cca2": "KR", "cca3": "KOR"},
+	{"name": {"common": "Koreatown"}, "capital": ["Nowhere"], "population": 1, "currencies": {"USD": {"symbol": "$"}}, "cca3": "XKT"}
+]`
+
+func TestWordMatches(t *testing.T) {
+	countries, err := decodeUpstream([]byte(koreasBody))
+	assert.NoError(t, err)
+
+	matches := wordMatches("korea", countries)
+
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "KOR", matches[0].CCA3)
+	assert.Equal(t, "PRK", matches[1].CCA3)
+}
+
+func TestChoose(t *testing.T) {
+	candidates := []models.CountryDetails{
+		{Name: models.CountryName{Common: "Republic of the Congo"}, CCA3: "COG", Population: 5657000},
+		{Name: models.CountryName{Common: "DR Congo"}, CCA3: "COD", Population: 108407721},
+	}
+
+	c, err := choose("Congo", candidates, TieBreakFirst)
+	assert.NoError(t, err)
+	assert.Equal(t, "COG", c.CCA3)
+
+	c, err = choose("Congo", candidates, TieBreakPopulous)
+	assert.NoError(t, err)
+	assert.Equal(t, "COD", c.CCA3)
+
+	_, err = choose("Congo", candidates, TieBreakExact)
+	assert.ErrorIs(t, err, ErrAmbiguous)
+
+	c, err = choose("dr congo", candidates, TieBreakExact)
+	assert.NoError(t, err)
+	assert.Equal(t, "COD", c.CCA3)
+
+	_, err = choose("Congo", candidates, TieBreakNone)
+	var ambiguous *AmbiguousError
+	assert.True(t, errors.As(err, &ambiguous))
+	assert.Len(t, ambiguous.Candidates, 2)
+}
+
+func TestParseTieBreak(t *testing.T) {
+	policy, err := ParseTieBreak("populous")
+	assert.NoError(t, err)
+	assert.Equal(t, TieBreakPopulous, policy)
+
+	_, err = ParseTieBreak("random")
+	assert.ErrorIs(t, err, ErrInvalidTieBreak)
+}
+
+func TestGetCountryDetailsByName_AmbiguousWord(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Korea?fullText=true").Return(nil, http_client.ErrNotFound)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(koreasBody), nil).Times(3)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryDetailsByName(context.Background(), "Korea")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+
+	country, err := ncs.GetCountryDetailsByNameTieBreak(context.Background(), "Korea", TieBreakPopulous)
+	assert.NoError(t, err)
+	assert.Equal(t, "South Korea", country.Name.Common)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryDetailsByName_AmbiguousAltSpelling(t *testing.T) {
+	congos := `[
+		{"name": {"common": "Republic of the Congo", "official": "Republic of the Congo"}, "capital": ["Brazzaville"], "population": 5657000, "currencies": {"XAF": {"symbol": "Fr"}}, "cca2": "CG", "cca3": "COG", "altSpellings": ["CG", "Congo", "Congo-Brazzaville"], "translations": {"ita": {"common": "Congo", "official": "Repubblica del Congo"}}},
+		{"name": {"common": "DR Congo", "official": "Democratic Republic of the Congo"}, "capital": ["Kinshasa"], "population": 108407721, "currencies": {"CDF": {"symbol": "FC"}}, "cca2": "CD", "cca3": "COD", "altSpellings": ["CD", "DR Congo", "Congo-Kinshasa", "DRC"]}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Congo?fullText=true").Return(nil, http_client.ErrNotFound)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(congos), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	// "Congo" is an alternate spelling of COG only, but it is also part of
+	// COD's name, so it stays ambiguous instead of resolving to COG.
+	for range 2 {
+		_, err := ncs.GetCountryDetailsByName(context.Background(), "Congo")
+		var ambiguous *AmbiguousError
+		assert.ErrorAs(t, err, &ambiguous)
+		assert.Len(t, ambiguous.Candidates, 2)
+	}
+
+	country, err := ncs.GetCountryDetailsByName(context.Background(), "Congo-Brazzaville")
+	assert.NoError(t, err)
+	assert.Equal(t, "COG", country.CCA3)
+}
+
+func TestGetCountryDetailsByName_SeveralUpstreamResults(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Koreas?fullText=true").Return([]byte(koreasBody), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryDetailsByName(context.Background(), "Koreas")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+
+	country, err := ncs.GetCountryDetailsByNameTieBreak(context.Background(), "Koreas", TieBreakFirst)
+	assert.NoError(t, err)
+	assert.Equal(t, "North Korea", country.Name.Common)
+}
@@ -0,0 +1,49 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/service/borders"
+	http_client "country-search-api/pkg/service/client"
+	"errors"
+	"fmt"
+)
+
+// BorderRoute returns a shortest overland route between two countries given
+// by code, both ends included.
+func (cs *countryService) BorderRoute(ctx context.Context, from, to string) ([]borders.Member, error) {
+	graph, err := cs.borders.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	route, err := graph.Path(from, to)
+	return route, borderError(err)
+}
+
+// BorderNeighbors returns every country within hops land-border crossings
+// of the country with the given code.
+func (cs *countryService) BorderNeighbors(ctx context.Context, code string, hops int) ([]borders.Neighbor, error) {
+	graph, err := cs.borders.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	neighbors, err := graph.Neighbors(code, hops)
+	return neighbors, borderError(err)
+}
+
+// BorderComponents splits the countries into landmasses connected by land
+// borders, largest first.
+func (cs *countryService) BorderComponents(ctx context.Context) ([]borders.Component, error) {
+	graph, err := cs.borders.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graph.Components(), nil
+}
+
+// borderError reports countries missing from the graph as not found.
+func borderError(err error) error {
+	if errors.Is(err, borders.ErrUnknownCountry) {
+		return fmt.Errorf("%w: %w", http_client.ErrNotFound, err)
+	}
+	return err
+}
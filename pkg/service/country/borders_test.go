@@ -0,0 +1,51 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/service/borders"
+	http_client "country-search-api/pkg/service/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBorderRoute_RebuildsOnRefresh(t *testing.T) {
+	before := `[
+		{"name": {"common": "Haiti"}, "capital": ["Port-au-Prince"], "population": 11402533, "currencies": {"HTG": {"symbol": "G"}}, "cca2": "HT", "cca3": "HTI"},
+		{"name": {"common": "Dominican Republic"}, "capital": ["Santo Domingo"], "population": 10847904, "currencies": {"DOP": {"symbol": "$"}}, "cca2": "DO", "cca3": "DOM"}
+	]`
+	after := `[
+		{"name": {"common": "Haiti"}, "capital": ["Port-au-Prince"], "population": 11402533, "currencies": {"HTG": {"symbol": "G"}}, "cca2": "HT", "cca3": "HTI", "borders": ["DOM"]},
+		{"name": {"common": "Dominican Republic"}, "capital": ["Santo Domingo"], "population": 10847904, "currencies": {"DOP": {"symbol": "$"}}, "cca2": "DO", "cca3": "DOM", "borders": ["HTI"]}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(before), nil).Times(3)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(after), nil).Times(3)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+	ctx := context.Background()
+
+	_, err := ncs.BorderRoute(ctx, "HT", "DO")
+	assert.ErrorIs(t, err, borders.ErrNoRoute)
+
+	components, err := ncs.BorderComponents(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, components, 2)
+
+	_, err = ncs.RefreshDataset(ctx)
+	assert.NoError(t, err)
+
+	route, err := ncs.BorderRoute(ctx, "HT", "DO")
+	assert.NoError(t, err)
+	assert.Equal(t, []borders.Member{{CCA3: "HTI", Name: "Haiti"}, {CCA3: "DOM", Name: "Dominican Republic"}}, route)
+
+	neighbors, err := ncs.BorderNeighbors(ctx, "DOM", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []borders.Neighbor{{Member: borders.Member{CCA3: "HTI", Name: "Haiti"}, Hops: 1}}, neighbors)
+
+	_, err = ncs.BorderNeighbors(ctx, "XK", 1)
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
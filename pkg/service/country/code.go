@@ -0,0 +1,149 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/cache"
+	http_client "country-search-api/pkg/service/client"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxCodesPerRequest bounds how many codes a single batch lookup accepts.
+const maxCodesPerRequest = 50
+
+var ErrInvalidCode = errors.New("invalid country code")
+
+// codePattern accepts ISO 3166-1 alpha-2, alpha-3 and numeric codes.
+var codePattern = regexp.MustCompile(`^(?:[A-Z]{2,3}|[0-9]{3})$`)
+
+// NormalizeCode upper-cases code and checks that it looks like an ISO 3166-1
+// code, so malformed input never reaches the upstream API.
+func NormalizeCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !codePattern.MatchString(code) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+	return code, nil
+}
+
+// CodeLookup is the result of looking up several codes: the countries found,
+// in the order their codes were given, and the normalized codes no country
+// has.
+type CodeLookup struct {
+	Countries []models.CountryDetails
+	Missing   []string
+}
+
+func (cs *countryService) GetCountryByCode(ctx context.Context, code string) (models.CountryDetails, error) {
+	lookup, err := cs.GetCountriesByCodes(ctx, []string{code})
+	if err != nil {
+		return models.CountryDetails{}, err
+	}
+	return lookup.Countries[0], nil
+}
+
+// GetCountriesByCodes resolves codes in order. Codes already cached are served
+// locally and the rest are fetched from the data source in one request. Codes
+// no country has are reported in Missing; it fails with ErrNotFound only when
+// none is found.
+func (cs *countryService) GetCountriesByCodes(ctx context.Context, codes []string) (CodeLookup, error) {
+	if len(codes) == 0 {
+		return CodeLookup{}, fmt.Errorf("%w: no codes given", ErrInvalidCode)
+	}
+	if len(codes) > maxCodesPerRequest {
+		return CodeLookup{}, fmt.Errorf("%w: at most %d codes per request", ErrInvalidCode, maxCodesPerRequest)
+	}
+
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		c, err := NormalizeCode(code)
+		if err != nil {
+			return CodeLookup{}, err
+		}
+		normalized[i] = c
+	}
+
+	found := make(map[string]models.CountryDetails, len(normalized))
+	var missing []string
+	for _, code := range normalized {
+		if _, ok := found[code]; ok || slices.Contains(missing, code) {
+			continue
+		}
+		if country, ok := cache.Cache.Get(codeCacheKey(code)); ok {
+			logger.Log().Info("country details present in local cache:", "code", code)
+			found[code] = country.(models.CountryDetails)
+			continue
+		}
+		missing = append(missing, code)
+	}
+
+	if len(missing) > 0 {
+		if err := cs.fetchByCodes(ctx, missing, found); err != nil && !errors.Is(err, http_client.ErrNotFound) {
+			return CodeLookup{}, err
+		}
+	}
+
+	var lookup CodeLookup
+	for _, code := range normalized {
+		if country, ok := found[code]; ok {
+			lookup.Countries = append(lookup.Countries, country)
+		} else if !slices.Contains(lookup.Missing, code) {
+			lookup.Missing = append(lookup.Missing, code)
+		}
+	}
+
+	if len(lookup.Countries) == 0 {
+		return CodeLookup{}, fmt.Errorf("%w: %s", http_client.ErrNotFound, strings.Join(lookup.Missing, ", "))
+	}
+	return lookup, nil
+}
+
+// fetchByCodes fetches codes from the data source and records each result in
+// found under whichever of its codes was requested.
+func (cs *countryService) fetchByCodes(ctx context.Context, codes []string, found map[string]models.CountryDetails) error {
+	all, err := cs.src.byCodes(ctx, codes, nil)
+	if err != nil {
+		return err
+	}
+	countries := validOnly(all)
+
+	requested := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		requested[code] = true
+	}
+
+	for _, country := range countries {
+		cacheCountry(country)
+		for _, code := range country.Codes() {
+			if requested[code] {
+				found[code] = country
+			}
+		}
+	}
+
+	return nil
+}
+
+// cacheCountry stores country under its name and each of its codes so that
+// name and code lookups share the same entries. The writes are synchronous,
+// so none can land after a dataset refresh has emptied the cache.
+func cacheCountry(country models.CountryDetails) {
+	logger.Log().Info("storing country details in local cache:", "country", country.Name.Common)
+	cache.Cache.Set(nameCacheKey(country.Name.Common), country)
+	for _, code := range country.Codes() {
+		cache.Cache.Set(codeCacheKey(code), country)
+	}
+}
+
+func nameCacheKey(name string) string {
+	return "name:" + strings.ToLower(strings.TrimSpace(name))
+}
+
+func codeCacheKey(code string) string {
+	return "code:" + code
+}
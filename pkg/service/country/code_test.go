@@ -0,0 +1,113 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/cache"
+	http_client "country-search-api/pkg/service/client"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeCode(t *testing.T) {
+	for _, code := range []string{"in", "IND", " 356 ", "jp"} {
+		_, err := NormalizeCode(code)
+		assert.NoError(t, err, code)
+	}
+
+	for _, code := range []string{"", "I", "INDI", "35", "3a6", "I-N"} {
+		_, err := NormalizeCode(code)
+		assert.ErrorIs(t, err, ErrInvalidCode, code)
+	}
+}
+
+func TestGetCountriesByCodes_Success(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[
+		{"name": {"common": "Japan"}, "capital": ["Tokyo"], "population": 125836021, "currencies": {"JPY": {"symbol": "¥"}}, "cca2": "JP", "cca3": "JPN", "ccn3": "392"},
+		{"name": {"common": "Nepal"}, "capital": ["Kathmandu"], "population": 29136808, "currencies": {"NPR": {"symbol": "₨"}}, "cca2": "NP", "cca3": "NPL", "ccn3": "524"}
+	]`
+
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(func(endpoint string) bool {
+		return strings.HasSuffix(endpoint, "/alpha?codes=NPL%2CJP")
+	})).Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	lookup, err := ncs.GetCountriesByCodes(context.Background(), []string{"npl", "jp"})
+
+	assert.NoError(t, err)
+	assert.Len(t, lookup.Countries, 2)
+	assert.Equal(t, "Nepal", lookup.Countries[0].Name.Common)
+	assert.Equal(t, "Japan", lookup.Countries[1].Name.Common)
+	assert.Empty(t, lookup.Missing)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountriesByCodes_ReportsMissing(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[{"name": {"common": "Laos"}, "capital": ["Vientiane"], "population": 7275556, "currencies": {"LAK": {"symbol": "₭"}}, "cca2": "LA", "cca3": "LAO", "ccn3": "418"}]`
+
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(func(endpoint string) bool {
+		return strings.HasSuffix(endpoint, "/alpha?codes=QQ%2CLAO%2C999")
+	})).Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	lookup, err := ncs.GetCountriesByCodes(context.Background(), []string{"qq", "lao", "999", "QQ"})
+
+	assert.NoError(t, err)
+	assert.Len(t, lookup.Countries, 1)
+	assert.Equal(t, "Laos", lookup.Countries[0].Name.Common)
+	assert.Equal(t, []string{"QQ", "999"}, lookup.Missing)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryByCode_InvalidCode(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryByCode(context.Background(), "ABCD")
+
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	mockClient.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestGetCountryByCode_NotFound(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.Anything).Return(nil, http_client.ErrNotFound).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryByCode(context.Background(), "ZZ")
+
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryByCode_SharesCacheWithName(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[{"name": {"common": "Peru"}, "capital": ["Lima"], "population": 32971846, "currencies": {"PEN": {"symbol": "S/ "}}, "cca2": "PE", "cca3": "PER", "ccn3": "604"}]`
+
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryByName(context.Background(), "Peru")
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, ok := cache.Cache.Get(codeCacheKey("604"))
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	country, err := ncs.GetCountryByCode(context.Background(), "pe")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.Country{Name: "Peru", Capital: "Lima", Currency: "S/ ", CurrencyCode: "PEN", Population: 32971846}, country.Summary())
+	mockClient.AssertExpectations(t)
+}
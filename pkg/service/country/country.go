@@ -4,69 +4,223 @@ import (
 	"context"
 	"country-search-api/pkg/logger"
 	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/alias"
+	"country-search-api/pkg/service/autocomplete"
+	"country-search-api/pkg/service/borders"
 	"country-search-api/pkg/service/cache"
 	http_client "country-search-api/pkg/service/client"
-	"fmt"
-	"net/url"
-
-	"github.com/tidwall/gjson"
+	"country-search-api/pkg/service/dataset"
+	"country-search-api/pkg/service/flags"
+	"country-search-api/pkg/service/fuzzy"
+	"country-search-api/pkg/service/geo"
+	"country-search-api/pkg/service/ipdb"
+	"country-search-api/pkg/service/money"
+	"country-search-api/pkg/service/stats"
+	"country-search-api/pkg/service/timezone"
+	"errors"
+	"net/netip"
+	"sync/atomic"
+	"time"
 )
 
 // const defaultBaseURL = "https://restcountries.com/v3.1"
 
 type CountryService interface {
+	// GetCountryByName returns the summary view of the country named name.
 	GetCountryByName(ctx context.Context, name string) (models.Country, error)
+	GetCountryDetailsByName(ctx context.Context, name string) (models.CountryDetails, error)
+	GetCountryDetailsByNameFuzzy(ctx context.Context, name string) (models.CountryDetails, error)
+	GetCountryDetailsByNameTieBreak(ctx context.Context, name string, policy TieBreak) (models.CountryDetails, error)
+	// GetCountryFieldsByName returns a record with only fields populated.
+	GetCountryFieldsByName(ctx context.Context, name string, fields []string, policy TieBreak) (models.CountryDetails, error)
+	SearchCountries(ctx context.Context, name string) ([]models.CountryDetails, error)
+	GetCountryByCode(ctx context.Context, code string) (models.CountryDetails, error)
+	GetCountriesByCodes(ctx context.Context, codes []string) (CodeLookup, error)
+	ListCountries(ctx context.Context, opts ListOptions) (Page, error)
+	SuggestCountries(ctx context.Context, prefix string, limit int) ([]autocomplete.Suggestion, error)
+	BorderRoute(ctx context.Context, from, to string) ([]borders.Member, error)
+	BorderNeighbors(ctx context.Context, code string, hops int) ([]borders.Neighbor, error)
+	BorderComponents(ctx context.Context) ([]borders.Component, error)
+	Distance(ctx context.Context, from, to string, anchor geo.Anchor) (geo.Leg, error)
+	NearestCountries(ctx context.Context, p models.LatLng, k int, anchor geo.Anchor) ([]geo.Hit, error)
+	CountryAt(ctx context.Context, p models.LatLng) (models.CountryDetails, error)
+	CountryByIP(ctx context.Context, addr netip.Addr) (models.CountryDetails, error)
+	LocalTime(ctx context.Context, code string) (timezone.CountryTime, error)
+	BusinessOverlap(ctx context.Context, from, to string, hours timezone.BusinessHours) (timezone.Overlap, error)
+	ConvertCurrency(ctx context.Context, amount money.Decimal, from, to CurrencyRef) (money.Conversion, error)
+	Stats(ctx context.Context, by stats.Dimension) (stats.Report, error)
+	TopCountries(ctx context.Context, q stats.TopQuery) ([]stats.Ranked, error)
+	FlagEmoji(ctx context.Context, code string) (models.Flag, error)
+	FlagImage(ctx context.Context, code string, format flags.Format) (flags.Image, error)
+	RefreshDataset(ctx context.Context) (dataset.Update, error)
+	DatasetHistory(limit int) []dataset.Update
 }
 
 type countryService struct {
-	httpClient http_client.ClientInf
-	baseURL    string
+	src      source
+	dataset  *dataset.Store
+	names    *dataset.Derived[*fuzzy.Index]
+	prefixes *dataset.Derived[*autocomplete.Trie]
+	// namesFailedAt is when loading the dataset for suggestions last
+	// failed, in Unix nanoseconds.
+	namesFailedAt atomic.Int64
+
+	aliases       map[string]string
+	configAliases *alias.Table
+	aliasTable    *dataset.Derived[*alias.Table]
+	codes         *dataset.Derived[map[string]models.CountryDetails]
+	borders       *dataset.Derived[*borders.Graph]
+	countryPoints *dataset.Derived[*geo.Index]
+	capitalPoints *dataset.Derived[*geo.Index]
+	stats         *dataset.Derived[*stats.Summary]
+	boundaries    *geo.Boundaries
+	ipdb          ipdb.Database
+	rates         money.Provider
+	flags         flags.Store
+	now           func() time.Time
+
+	history *dataset.History
+	hooks   []func(dataset.Update)
 }
 
-func NewCountryService(httpClient http_client.ClientInf, baseURL string) CountryService {
-	return &countryService{
-		httpClient: httpClient,
-		baseURL:    baseURL,
+func NewCountryService(httpClient http_client.ClientInf, baseURL string, opts ...Option) CountryService {
+	return newCountryService(&upstreamSource{httpClient: httpClient, baseURL: baseURL}, opts...)
+}
+
+func newCountryService(src source, opts ...Option) *countryService {
+	cs := &countryService{src: src, now: time.Now}
+	for _, opt := range opts {
+		opt(cs)
 	}
+
+	cs.dataset = dataset.NewStore(cs.src.all)
+	cs.names = dataset.NewDerived(cs.dataset, fuzzy.NewIndex)
+	cs.prefixes = dataset.NewDerived(cs.dataset, autocomplete.NewTrie)
+	cs.configAliases = alias.NewTable(nil, cs.aliases)
+	cs.aliasTable = dataset.NewDerived(cs.dataset, cs.newAliasTable)
+	cs.codes = dataset.NewDerived(cs.dataset, indexByCode)
+	cs.borders = dataset.NewDerived(cs.dataset, borders.NewGraph)
+	cs.countryPoints = dataset.NewDerived(cs.dataset, func(c []models.CountryDetails) *geo.Index {
+		return geo.NewIndex(c, geo.AnchorCountry)
+	})
+	cs.capitalPoints = dataset.NewDerived(cs.dataset, func(c []models.CountryDetails) *geo.Index {
+		return geo.NewIndex(c, geo.AnchorCapital)
+	})
+	cs.stats = dataset.NewDerived(cs.dataset, stats.New)
+	cs.history = dataset.NewHistory(HistorySize)
+	return cs
 }
 
 func (cs *countryService) GetCountryByName(ctx context.Context, name string) (models.Country, error) {
+	details, err := cs.GetCountryDetailsByName(ctx, name)
+	if err != nil {
+		return models.Country{}, err
+	}
+	return details.Summary(), nil
+}
+
+func (cs *countryService) GetCountryDetailsByName(ctx context.Context, name string) (models.CountryDetails, error) {
+	return cs.GetCountryDetailsByNameTieBreak(ctx, name, TieBreakNone)
+}
+
+// GetCountryDetailsByNameTieBreak looks up name and, when it matches several
+// countries, lets policy choose one or reports an AmbiguousError.
+func (cs *countryService) GetCountryDetailsByNameTieBreak(ctx context.Context, name string, policy TieBreak) (models.CountryDetails, error) {
 	// fmt.Println("GetCountryByName")
+	if country, ok, err := cs.lookupAlias(ctx, name); ok {
+		return country, err
+	}
+
 	logger.Log().Info("searching country details in local cache:", "country", name)
-	if country, ok := cache.Cache.Get(name); ok {
+	if country, ok := cache.Cache.Get(nameCacheKey(name)); ok {
 		logger.Log().Info("country details present in local cache:", "country", name)
-		return country.(models.Country), nil
+		return country.(models.CountryDetails), nil
 	}
 
 	logger.Log().Info("country details does not exist in local cache:", "country", name)
-	logger.Log().Info("searching in 3rd party API:", "country", name)
-
-	escaped := url.PathEscape(name)
-	endpoint := fmt.Sprintf(
-		"%s/name/%s?fields=name,capital,currencies,population&fullText=true",
-		cs.baseURL,
-		escaped,
-	)
 
-	countryBytes, err := cs.httpClient.Get(ctx, endpoint)
+	found, err := cs.src.byName(ctx, name, true, nil)
+	if errors.Is(err, http_client.ErrNotFound) {
+		logger.Log().Info("country not found:", "country", name)
+		return cs.resolveMiss(ctx, name, policy)
+	}
 	if err != nil {
-		logger.Log().Error("unable to get country details from 3rd party API:", "country", name)
-		return models.Country{}, err
+		return models.CountryDetails{}, err
 	}
 
-	country := models.Country{
-		Name:       gjson.GetBytes(countryBytes, "0.name.common").String(),
-		Currency:   gjson.GetBytes(countryBytes, "0.currencies.*.symbol").String(),
-		Capital:    gjson.GetBytes(countryBytes, "0.capital.0").String(),
-		Population: gjson.GetBytes(countryBytes, "0.population").Int(),
+	countries := validOnly(found)
+	if len(countries) == 0 {
+		// fmt.Println(country)
+		return models.CountryDetails{}, http_client.ErrInvalidData
+	}
+	for _, country := range countries {
+		cacheCountry(country)
 	}
 
-	if !country.Validate() {
-		// fmt.Println(country)
-		return models.Country{}, http_client.ErrInvalidData
+	if len(countries) > 1 {
+		logger.Log().Info("country name matches several countries:", "country", name, "matches", len(countries))
+		return choose(name, countries, policy)
 	}
 
-	logger.Log().Info("storing country details in local cache:", "country", name)
-	go cache.Cache.Set(name, country)
+	country := countries[0]
+	if nameCacheKey(name) != nameCacheKey(country.Name.Common) {
+		cache.Cache.Set(nameCacheKey(name), country)
+	}
 	return country, nil
 }
+
+// resolveMiss handles a name upstream does not know. Loading the dataset for
+// suggestions may reveal name as an alternate spelling, or as a word shared
+// by several country names such as "Korea".
+func (cs *countryService) resolveMiss(ctx context.Context, name string, policy TieBreak) (models.CountryDetails, error) {
+	notFound := cs.notFound(ctx, name)
+
+	if country, ok, err := cs.lookupAlias(ctx, name); ok {
+		return country, err
+	}
+
+	if countries, _, ok := cs.dataset.Peek(); ok {
+		if candidates := validOnly(wordMatches(name, countries)); len(candidates) > 1 {
+			logger.Log().Info("country name matches several countries:", "country", name, "matches", len(candidates))
+			return choose(name, candidates, policy)
+		}
+	}
+
+	return models.CountryDetails{}, notFound
+}
+
+// SearchCountries returns every country whose name partially matches name,
+// ranked exact match first, then prefix, then substring.
+func (cs *countryService) SearchCountries(ctx context.Context, name string) ([]models.CountryDetails, error) {
+	key := searchCacheKey(name)
+	logger.Log().Info("searching partial matches in local cache:", "query", name)
+	if countries, ok := cache.Cache.Get(key); ok {
+		logger.Log().Info("partial matches present in local cache:", "query", name)
+		return countries.([]models.CountryDetails), nil
+	}
+
+	logger.Log().Info("partial matches do not exist in local cache:", "query", name)
+
+	all, err := cs.src.byName(ctx, name, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	found := validOnly(all)
+	if len(found) == 0 {
+		return nil, http_client.ErrInvalidData
+	}
+
+	matches := make([]match, 0, len(found))
+	for _, country := range found {
+		matches = append(matches, match{
+			country: country,
+			rank:    rankMatch(name, country.Name.Common, country.Name.Official),
+		})
+	}
+	countries := sortMatches(matches)
+
+	logger.Log().Info("storing partial matches in local cache:", "query", name)
+	cache.Cache.Set(key, countries)
+	return countries, nil
+}
@@ -5,6 +5,7 @@ import (
 	mock_http_client "country-search-api/mock/ClientInf"
 	"country-search-api/pkg/models"
 	http_client "country-search-api/pkg/service/client"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,10 +25,11 @@ func TestGetCountryByName_Success(t *testing.T) {
 	]`
 
 	expected := models.Country{
-		Name:       "India",
-		Capital:    "New Delhi",
-		Population: 1400000000,
-		Currency:   "₹",
+		Name:         "India",
+		Capital:      "New Delhi",
+		Population:   1400000000,
+		Currency:     "₹",
+		CurrencyCode: "INR",
 	}
 
 	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
@@ -50,9 +52,52 @@ func TestGetCountryByName_InvalidData(t *testing.T) {
 	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
 	ncs := NewCountryService(mockClient, "defaultBaseURL")
 
-	country, err := ncs.GetCountryByName(context.Background(), "India")
+	// Cache writes are synchronous, so a name another test cached would be
+	// answered without asking upstream.
+	country, err := ncs.GetCountryByName(context.Background(), "Suriname")
 
 	assert.ErrorIs(t, err, http_client.ErrInvalidData)
 	assert.Equal(t, country, models.Country{})
 	mockClient.AssertExpectations(t)
 }
+
+func TestSearchCountries_RankedMatches(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[
+		{"name": {"common": "Papua New Guinea", "official": "Independent State of Papua New Guinea"}, "capital": ["Port Moresby"], "population": 8947027, "currencies": {"PGK": {"symbol": "K"}}},
+		{"name": {"common": "Guinea-Bissau", "official": "Republic of Guinea-Bissau"}, "capital": ["Bissau"], "population": 1967998, "currencies": {"XOF": {"symbol": "Fr"}}},
+		{"name": {"common": "Equatorial Guinea", "official": "Republic of Equatorial Guinea"}, "capital": ["Malabo"], "population": 1402985, "currencies": {"XAF": {"symbol": "Fr"}}},
+		{"name": {"common": "Guinea", "official": "Republic of Guinea"}, "capital": ["Conakry"], "population": 13132792, "currencies": {"GNF": {"symbol": "Fr"}}}
+	]`
+
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(func(endpoint string) bool {
+		return !strings.Contains(endpoint, "fullText")
+	})).Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	countries, err := ncs.SearchCountries(context.Background(), "Guinea")
+
+	assert.NoError(t, err)
+	names := make([]string, 0, len(countries))
+	for _, c := range countries {
+		names = append(names, c.Name.Common)
+	}
+	assert.Equal(t, []string{"Guinea", "Guinea-Bissau", "Equatorial Guinea", "Papua New Guinea"}, names)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSearchCountries_NoValidMatches(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[{ "name": {"common": "Antarctica"} }]` // missing required fields
+
+	mockClient.On("Get", mock.Anything, mock.Anything).Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	countries, err := ncs.SearchCountries(context.Background(), "Antarc")
+
+	assert.ErrorIs(t, err, http_client.ErrInvalidData)
+	assert.Empty(t, countries)
+	mockClient.AssertExpectations(t)
+}
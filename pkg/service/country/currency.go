@@ -0,0 +1,90 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/money"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithExchangeRates enables ConvertCurrency with rates from provider.
+func WithExchangeRates(provider money.Provider) Option {
+	return func(cs *countryService) {
+		cs.rates = provider
+	}
+}
+
+// CurrencyRef names a currency either by its ISO 4217 Code or as the
+// currency of a Country, given by code or name.
+type CurrencyRef struct {
+	Code    string
+	Country string
+}
+
+// ConvertCurrency converts amount between two currencies at the configured
+// exchange rates. A country with several currencies is converted with the
+// first of them that has a rate.
+func (cs *countryService) ConvertCurrency(ctx context.Context, amount money.Decimal, from, to CurrencyRef) (money.Conversion, error) {
+	if cs.rates == nil {
+		return money.Conversion{}, money.ErrNoRates
+	}
+	rates, err := cs.rates.Rates(ctx)
+	if err != nil {
+		return money.Conversion{}, err
+	}
+
+	fromCode, fromCountry, err := cs.currencyOf(ctx, from, rates)
+	if err != nil {
+		return money.Conversion{}, err
+	}
+	toCode, toCountry, err := cs.currencyOf(ctx, to, rates)
+	if err != nil {
+		return money.Conversion{}, err
+	}
+
+	conversion, err := money.Convert(rates, amount, fromCode, toCode)
+	if err != nil {
+		return money.Conversion{}, err
+	}
+	conversion.From.Country = fromCountry
+	conversion.To.Country = toCountry
+	return conversion, nil
+}
+
+// currencyOf resolves ref to an ISO 4217 code and, when ref names a country,
+// that country's common name.
+func (cs *countryService) currencyOf(ctx context.Context, ref CurrencyRef, rates money.Rates) (string, string, error) {
+	if ref.Country == "" {
+		return ref.Code, "", nil
+	}
+
+	country, err := cs.countryByCodeOrName(ctx, ref.Country)
+	if err != nil {
+		return "", "", err
+	}
+	if len(country.Currencies) == 0 {
+		return "", "", fmt.Errorf("%w: %s has no currency", money.ErrUnknownCurrency, country.Name.Common)
+	}
+	for _, c := range country.Currencies {
+		if _, ok := rates.Quotes[c.Code]; ok {
+			return c.Code, country.Name.Common, nil
+		}
+	}
+	return "", "", fmt.Errorf("%w for the currency of %s", money.ErrNoRate, country.Name.Common)
+}
+
+// countryByCodeOrName treats query as an ISO 3166-1 code when it looks like
+// one and is known, and as a name otherwise, so that "JP", "Japan" and the
+// alias "UK" all resolve.
+func (cs *countryService) countryByCodeOrName(ctx context.Context, query string) (models.CountryDetails, error) {
+	if code, err := NormalizeCode(query); err == nil {
+		country, err := cs.countryByCode(ctx, code)
+		if !errors.Is(err, http_client.ErrNotFound) {
+			return country, err
+		}
+	}
+	return cs.GetCountryDetailsByName(ctx, strings.TrimSpace(query))
+}
@@ -0,0 +1,65 @@
+package country
+
+import (
+	"context"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/money"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const currencyBody = `[
+	{"name": {"common": "India"}, "capital": ["New Delhi"], "population": 1380004385, "currencies": {"INR": {"name": "Indian rupee", "symbol": "₹"}}, "cca2": "IN", "cca3": "IND"},
+	{"name": {"common": "Japan"}, "capital": ["Tokyo"], "population": 125836021, "currencies": {"JPY": {"name": "Japanese yen", "symbol": "¥"}}, "cca2": "JP", "cca3": "JPN"},
+	{"name": {"common": "Kuwait"}, "capital": ["Kuwait City"], "population": 4270563, "currencies": {"KWD": {"name": "Kuwaiti dinar", "symbol": "د.ك"}}, "cca2": "KW", "cca3": "KWT"},
+	{"name": {"common": "Lesotho"}, "capital": ["Maseru"], "population": 2142252, "currencies": {"LSL": {"name": "Lesotho loti", "symbol": "L"}, "ZAR": {"name": "South African rand", "symbol": "R"}}, "cca2": "LS", "cca3": "LSO"},
+	{"name": {"common": "Tuvalu"}, "capital": ["Funafuti"], "population": 11792, "currencies": {"TVD": {"name": "Tuvaluan dollar", "symbol": "$"}}, "cca2": "TV", "cca3": "TUV"}
+]`
+
+var fakeRates = money.Static{
+	Base: "EUR",
+	Quotes: map[string]money.Decimal{
+		"EUR": money.MustParseDecimal("1"),
+		"INR": money.MustParseDecimal("97.45"),
+		"JPY": money.MustParseDecimal("163.2"),
+		"KWD": money.MustParseDecimal("0.3371"),
+		"ZAR": money.MustParseDecimal("20.15"),
+	},
+}
+
+func TestConvertCurrency(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(currencyBody), WithExchangeRates(fakeRates))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	c, err := ncs.ConvertCurrency(ctx, money.MustParseDecimal("100"), CurrencyRef{Code: "INR"}, CurrencyRef{Country: "jp"})
+	assert.NoError(t, err)
+	assert.Equal(t, money.Amount{Amount: "100", Currency: "INR"}, c.From)
+	assert.Equal(t, money.Amount{Amount: "167", Currency: "JPY", Country: "Japan"}, c.To)
+
+	c, err = ncs.ConvertCurrency(ctx, money.MustParseDecimal("2.5"), CurrencyRef{Country: "Kuwait"}, CurrencyRef{Code: "eur"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Kuwait", c.From.Country)
+	assert.Equal(t, "7.42", c.To.Amount) // 2.5 / 0.3371 = 7.4161...
+
+	// Lesotho's loti has no rate, so its other currency, the rand, is used.
+	c, err = ncs.ConvertCurrency(ctx, money.MustParseDecimal("1000"), CurrencyRef{Country: "LSO"}, CurrencyRef{Code: "KWD"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ZAR", c.From.Currency)
+	assert.Equal(t, "16.730", c.To.Amount)
+
+	_, err = ncs.ConvertCurrency(ctx, money.MustParseDecimal("1"), CurrencyRef{Country: "TUV"}, CurrencyRef{Code: "EUR"})
+	assert.ErrorIs(t, err, money.ErrNoRate)
+
+	_, err = ncs.ConvertCurrency(ctx, money.MustParseDecimal("1"), CurrencyRef{Code: "EUR"}, CurrencyRef{Country: "XX"})
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+
+	_, err = ncs.ConvertCurrency(ctx, money.MustParseDecimal("1"), CurrencyRef{Code: "EURO"}, CurrencyRef{Code: "JPY"})
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+
+	ncs, err = NewOfflineCountryService([]byte(currencyBody))
+	assert.NoError(t, err)
+	_, err = ncs.ConvertCurrency(ctx, money.MustParseDecimal("1"), CurrencyRef{Code: "INR"}, CurrencyRef{Code: "JPY"})
+	assert.ErrorIs(t, err, money.ErrNoRates)
+}
@@ -0,0 +1,107 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/cache"
+	http_client "country-search-api/pkg/service/client"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidField = errors.New("invalid field")
+
+// NormalizeFields trims, de-duplicates and sorts fields and checks each one
+// against the fields of models.CountryDetails.
+func NormalizeFields(fields []string) ([]string, error) {
+	normalized := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !models.IsDetailField(f) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidField, f)
+		}
+		normalized = append(normalized, f)
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: no fields given", ErrInvalidField)
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
+// GetCountryFieldsByName looks up the country named name, fetching only the
+// upstream fields behind fields. The result is cached under its field set so
+// it is never mistaken for a full record. Names upstream does not know, or
+// knows as several countries, are resolved like GetCountryDetailsByNameTieBreak
+// does, so the answer matches the lookup without fields.
+func (cs *countryService) GetCountryFieldsByName(ctx context.Context, name string, fields []string, policy TieBreak) (models.CountryDetails, error) {
+	fields, err := NormalizeFields(fields)
+	if err != nil {
+		return models.CountryDetails{}, err
+	}
+
+	fullKey := nameCacheKey(name)
+	fetch := func() ([]models.CountryDetails, error) {
+		return cs.src.byName(ctx, name, true, fields)
+	}
+	if code, _, isAlias := cs.aliasCode(name); isAlias {
+		fullKey = codeCacheKey(code)
+		fetch = func() ([]models.CountryDetails, error) {
+			return cs.src.byCodes(ctx, []string{code}, fields)
+		}
+	}
+
+	if country, ok := cache.Cache.Get(fullKey); ok {
+		logger.Log().Info("country details present in local cache:", "country", name)
+		return country.(models.CountryDetails), nil
+	}
+
+	key := fieldsCacheKey(fields) + fullKey
+	if country, ok := cache.Cache.Get(key); ok {
+		logger.Log().Info("projected country details present in local cache:", "country", name, "fields", fields)
+		return country.(models.CountryDetails), nil
+	}
+
+	countries, err := fetch()
+	if errors.Is(err, http_client.ErrNotFound) {
+		return cs.resolveMiss(ctx, name, policy)
+	}
+	if err != nil {
+		return models.CountryDetails{}, err
+	}
+	if len(countries) == 0 {
+		return models.CountryDetails{}, http_client.ErrNotFound
+	}
+	if len(countries) > 1 {
+		// Tie-breaks need complete records, such as the population.
+		logger.Log().Info("country name matches several countries:", "country", name, "matches", len(countries))
+		return cs.GetCountryDetailsByNameTieBreak(ctx, name, policy)
+	}
+
+	cache.Cache.Set(key, countries[0])
+	return countries[0], nil
+}
+
+// mapFields translates model fields into the upstream fields that back them.
+func mapFields(fields []string) []string {
+	var mapped []string
+	for _, f := range fields {
+		for _, u := range upstreamFields[f] {
+			if !slices.Contains(mapped, u) {
+				mapped = append(mapped, u)
+			}
+		}
+	}
+	return mapped
+}
+
+func fieldsCacheKey(fields []string) string {
+	return "fields:" + strings.Join(fields, ",") + ":"
+}
@@ -0,0 +1,109 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeFields(t *testing.T) {
+	fields, err := NormalizeFields([]string{" flag", "name", "flag", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"flag", "name"}, fields)
+
+	_, err = NormalizeFields([]string{"name", "gdp"})
+	assert.ErrorIs(t, err, ErrInvalidField)
+
+	_, err = NormalizeFields([]string{" "})
+	assert.ErrorIs(t, err, ErrInvalidField)
+}
+
+func TestUpstreamFields_CoverModel(t *testing.T) {
+	for _, f := range models.DetailFields() {
+		assert.NotEmpty(t, upstreamFields[f], f)
+	}
+
+	var mapped []string
+	for _, u := range upstreamFields {
+		mapped = append(mapped, u...)
+	}
+	assert.ElementsMatch(t, detailFields, mapped)
+}
+
+func TestGetCountryFieldsByName_FetchesOnlyRequestedFields(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	body := `[{"name": {"common": "Bhutan", "official": "Kingdom of Bhutan"}, "flag": "🇧🇹", "flags": {"png": "https://flagcdn.com/w320/bt.png"}}]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Bhutan?fullText=true&fields=flag,flags,name").
+		Return([]byte(body), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	country, err := ncs.GetCountryFieldsByName(context.Background(), "Bhutan", []string{"name", "flag"}, TieBreakNone)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bhutan", country.Name.Common)
+	assert.Equal(t, "🇧🇹", country.Flag.Emoji)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryFieldsByName_NotCachedAsFullRecord(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	projected := `[{"name": {"common": "Fiji"}}]`
+	full := `[{"name": {"common": "Fiji"}, "capital": ["Suva"], "population": 896444, "currencies": {"FJD": {"symbol": "$"}}}]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Fiji?fullText=true&fields=name").Return([]byte(projected), nil).Once()
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Fiji?fullText=true").Return([]byte(full), nil).Once()
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryFieldsByName(context.Background(), "Fiji", []string{"name"}, TieBreakNone)
+	assert.NoError(t, err)
+
+	country, err := ncs.GetCountryByName(context.Background(), "Fiji")
+	assert.NoError(t, err)
+	assert.Equal(t, "Suva", country.Capital)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryFieldsByName_AmbiguousNameResolvedLikeFullLookup(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	projected := `[{"name": {"common": "Samoa"}}, {"name": {"common": "American Samoa"}}]`
+	full := `[
+		{"name": {"common": "Samoa"}, "capital": ["Apia"], "population": 198410, "currencies": {"WST": {"symbol": "T"}}, "cca3": "WSM"},
+		{"name": {"common": "American Samoa"}, "capital": ["Pago Pago"], "population": 55197, "currencies": {"USD": {"symbol": "$"}}, "cca3": "ASM"}
+	]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Samoa?fullText=true&fields=capital").Return([]byte(projected), nil)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Samoa?fullText=true").Return([]byte(full), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryFieldsByName(context.Background(), "Samoa", []string{"capitals"}, TieBreakNone)
+	var ambiguous *AmbiguousError
+	assert.ErrorAs(t, err, &ambiguous)
+
+	country, err := ncs.GetCountryFieldsByName(context.Background(), "Samoa", []string{"capitals"}, TieBreakPopulous)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apia"}, country.Capitals)
+}
+
+func TestGetCountryFieldsByName_MissResolvedThroughDataset(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+
+	all := `[{"name": {"common": "Eswatini", "official": "Kingdom of Eswatini"}, "capital": ["Mbabane"], "population": 1160164, "currencies": {"SZL": {"symbol": "L"}}, "cca2": "SZ", "cca3": "SWZ", "altSpellings": ["SZ", "Swaziland"]}]`
+
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Swaziland?fullText=true&fields=capital").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(all), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	country, err := ncs.GetCountryFieldsByName(context.Background(), "Swaziland", []string{"capitals"}, TieBreakNone)
+	assert.NoError(t, err)
+	assert.Equal(t, "Eswatini", country.Name.Common)
+	assert.Equal(t, "alias", country.ResolvedFrom.Via)
+}
@@ -0,0 +1,53 @@
+package country
+
+import (
+	"country-search-api/pkg/models"
+	"strings"
+)
+
+// Filter selects countries by region, subregion, language and currency when
+// listing them. Empty fields are ignored and the remaining ones are combined
+// with AND.
+type Filter struct {
+	Region    string
+	Subregion string
+	// Language is an ISO 639-3 code such as "spa" or an English name.
+	Language string
+	// Currency is an ISO 4217 code such as "EUR" or an English name.
+	Currency string
+}
+
+// Matches reports whether country satisfies every non-empty field of f.
+func (f Filter) Matches(country models.CountryDetails) bool {
+	if f.Region != "" && !strings.EqualFold(f.Region, country.Region) {
+		return false
+	}
+	if f.Subregion != "" && !strings.EqualFold(f.Subregion, country.Subregion) {
+		return false
+	}
+	if f.Language != "" && !hasLanguage(country, f.Language) {
+		return false
+	}
+	if f.Currency != "" && !hasCurrency(country, f.Currency) {
+		return false
+	}
+	return true
+}
+
+func hasLanguage(country models.CountryDetails, want string) bool {
+	for _, l := range country.Languages {
+		if strings.EqualFold(l.Code, want) || strings.EqualFold(l.Name, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCurrency(country models.CountryDetails, want string) bool {
+	for _, c := range country.Currencies {
+		if strings.EqualFold(c.Code, want) || strings.EqualFold(c.Name, want) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package country
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const allCountriesBody = `[
+	{"name": {"common": "France"}, "capital": ["Paris"], "population": 67391582, "currencies": {"EUR": {"name": "Euro", "symbol": "€"}}, "cca2": "FR", "cca3": "FRA", "ccn3": "250", "region": "Europe", "subregion": "Western Europe", "languages": {"fra": "French"}},
+	{"name": {"common": "Belgium"}, "capital": ["Brussels"], "population": 11555997, "currencies": {"EUR": {"name": "Euro", "symbol": "€"}}, "cca2": "BE", "cca3": "BEL", "ccn3": "056", "region": "Europe", "subregion": "Western Europe", "languages": {"deu": "German", "fra": "French", "nld": "Dutch"}},
+	{"name": {"common": "Switzerland"}, "capital": ["Bern"], "population": 8654622, "currencies": {"CHF": {"name": "Swiss franc", "symbol": "Fr."}}, "cca2": "CH", "cca3": "CHE", "ccn3": "756", "region": "Europe", "subregion": "Western Europe", "languages": {"fra": "French", "gsw": "Swiss German"}},
+	{"name": {"common": "Senegal"}, "capital": ["Dakar"], "population": 16743930, "currencies": {"XOF": {"name": "West African CFA franc", "symbol": "Fr"}}, "cca2": "SN", "cca3": "SEN", "ccn3": "686", "region": "Africa", "subregion": "Western Africa", "languages": {"fra": "French"}},
+	{"name": {"common": "Antarctica"}, "population": 1000, "region": "Antarctic", "languages": {}}
+]`
+
+func TestFilter_Matches(t *testing.T) {
+	countries, err := decodeUpstream([]byte(allCountriesBody))
+	assert.NoError(t, err)
+
+	matching := func(f Filter) []string {
+		var codes []string
+		for _, c := range countries {
+			if f.Matches(c) {
+				codes = append(codes, c.CCA3)
+			}
+		}
+		return codes
+	}
+
+	assert.Equal(t, []string{"FRA", "BEL", "CHE"}, matching(Filter{Region: "europe", Language: "French"}))
+	assert.Equal(t, []string{"FRA", "BEL"}, matching(Filter{Language: "fra", Currency: "eur"}))
+	assert.Equal(t, []string{"SEN"}, matching(Filter{Subregion: "western africa", Currency: "West African CFA franc"}))
+	assert.Empty(t, matching(Filter{Region: "Africa", Currency: "EUR"}))
+	assert.Len(t, matching(Filter{}), 5)
+}
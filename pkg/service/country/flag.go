@@ -0,0 +1,43 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/flags"
+)
+
+// WithFlagImages enables FlagImage with images from store.
+func WithFlagImages(store flags.Store) Option {
+	return func(cs *countryService) {
+		cs.flags = store
+	}
+}
+
+// FlagEmoji returns the flag of the country with the given code, with its
+// emoji computed from the alpha-2 code.
+func (cs *countryService) FlagEmoji(ctx context.Context, code string) (models.Flag, error) {
+	country, err := cs.countryByCode(ctx, code)
+	if err != nil {
+		return models.Flag{}, err
+	}
+
+	emoji, err := flags.Emoji(country.CCA2)
+	if err != nil {
+		return models.Flag{}, err
+	}
+	flag := country.Flag
+	flag.Emoji = emoji
+	return flag, nil
+}
+
+// FlagImage returns the SVG or PNG flag of the country with the given code.
+func (cs *countryService) FlagImage(ctx context.Context, code string, format flags.Format) (flags.Image, error) {
+	if cs.flags == nil {
+		return flags.Image{}, flags.ErrNoImages
+	}
+	country, err := cs.countryByCode(ctx, code)
+	if err != nil {
+		return flags.Image{}, err
+	}
+	return cs.flags.Image(ctx, country, format)
+}
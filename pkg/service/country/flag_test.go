@@ -0,0 +1,58 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/flags"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const flagBody = `[
+	{"name": {"common": "Iceland"}, "capital": ["Reykjavik"], "population": 366425, "currencies": {"ISK": {"symbol": "kr"}}, "cca2": "IS", "cca3": "ISL", "flags": {"png": "https://flagcdn.com/w320/is.png", "svg": "https://flagcdn.com/is.svg", "alt": "The flag of Iceland has a blue field with a large white-edged red cross."}},
+	{"name": {"common": "Kosovo"}, "capital": ["Pristina"], "population": 1775378, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "UNK"}
+]`
+
+func TestFlagEmoji(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(flagBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	flag, err := ncs.FlagEmoji(ctx, "isl")
+	assert.NoError(t, err)
+	assert.Equal(t, models.Flag{
+		Emoji: "🇮🇸",
+		PNG:   "https://flagcdn.com/w320/is.png",
+		SVG:   "https://flagcdn.com/is.svg",
+		Alt:   "The flag of Iceland has a blue field with a large white-edged red cross.",
+	}, flag)
+
+	_, err = ncs.FlagEmoji(ctx, "UNK")
+	assert.ErrorIs(t, err, flags.ErrNoFlag)
+
+	_, err = ncs.FlagEmoji(ctx, "XX")
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+}
+
+func TestFlagImage(t *testing.T) {
+	dir := t.TempDir()
+	svg := []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "is.svg"), svg, 0o600))
+
+	ncs, err := NewOfflineCountryService([]byte(flagBody), WithFlagImages(flags.Dir(dir)))
+	assert.NoError(t, err)
+
+	img, err := ncs.FlagImage(context.Background(), "IS", flags.SVG)
+	assert.NoError(t, err)
+	assert.Equal(t, svg, img.Data)
+
+	ncs, err = NewOfflineCountryService([]byte(flagBody))
+	assert.NoError(t, err)
+
+	_, err = ncs.FlagImage(context.Background(), "IS", flags.SVG)
+	assert.ErrorIs(t, err, flags.ErrNoImages)
+}
@@ -0,0 +1,99 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/fuzzy"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxSuggestions bounds the "did you mean" list attached to a miss.
+const maxSuggestions = 5
+
+// namesBackoff is how long name misses go without suggestions after loading
+// the dataset for them failed, rather than retrying the load every time.
+const namesBackoff = 30 * time.Second
+
+// NotFoundError reports a name lookup that matched no country, together with
+// the closest known names. It unwraps to http_client.ErrNotFound.
+type NotFoundError struct {
+	Query       string
+	Suggestions []fuzzy.Suggestion
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("country %q not found", e.Query)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return http_client.ErrNotFound
+}
+
+// GetCountryDetailsByNameFuzzy behaves like GetCountryDetailsByName but, when
+// the name is unknown, answers with the single high-confidence fuzzy match if
+// there is one.
+func (cs *countryService) GetCountryDetailsByNameFuzzy(ctx context.Context, name string) (models.CountryDetails, error) {
+	country, err := cs.GetCountryDetailsByName(ctx, name)
+	if !errors.Is(err, http_client.ErrNotFound) {
+		return country, err
+	}
+
+	idx, ok := cs.nameIndex(ctx)
+	if !ok {
+		return models.CountryDetails{}, err
+	}
+
+	resolved, suggestion, ok := idx.Resolve(name)
+	if !ok {
+		return models.CountryDetails{}, err
+	}
+	if !resolved.Validate() {
+		return models.CountryDetails{}, http_client.ErrInvalidData
+	}
+
+	logger.Log().Info("resolved country by fuzzy match:", "query", name, "country", resolved.Name.Common, "score", suggestion.Score)
+	resolved.ResolvedFrom = &models.Resolution{
+		Query: name,
+		Via:   "fuzzy",
+		Name:  resolved.Name.Common,
+		CCA3:  resolved.CCA3,
+	}
+	return resolved, nil
+}
+
+// notFound builds the error for an unknown name, with suggestions when the
+// full dataset is available.
+func (cs *countryService) notFound(ctx context.Context, name string) error {
+	e := &NotFoundError{Query: name}
+	if idx, ok := cs.nameIndex(ctx); ok {
+		e.Suggestions = idx.Suggest(name, maxSuggestions)
+	}
+	return e
+}
+
+// nameIndex returns the fuzzy index over the dataset, loading the dataset if
+// it is not yet in memory. After a failed load it reports no index until
+// namesBackoff has passed, so misses do not each repeat the upstream calls.
+func (cs *countryService) nameIndex(ctx context.Context) (*fuzzy.Index, bool) {
+	if idx, ok := cs.names.Peek(); ok {
+		return idx, true
+	}
+	if failed := cs.namesFailedAt.Load(); failed != 0 && cs.now().Sub(time.Unix(0, failed)) < namesBackoff {
+		return nil, false
+	}
+
+	idx, err := cs.names.Get(ctx)
+	if err != nil {
+		logger.Log().Warn("unable to load country names for suggestions:", "error", err)
+		// A caller giving up says nothing about upstream.
+		if ctx.Err() == nil {
+			cs.namesFailedAt.Store(cs.now().UnixNano())
+		}
+		return nil, false
+	}
+	return idx, true
+}
@@ -0,0 +1,96 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/fuzzy"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func isAllEndpoint(endpoint string) bool {
+	return strings.Contains(endpoint, "/all?")
+}
+
+func TestGetCountryDetailsByName_NotFoundSuggestions(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Belgum?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(allCountriesBody), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryDetailsByName(context.Background(), "Belgum")
+
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+	var notFound *NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.NotEmpty(t, notFound.Suggestions)
+	assert.Equal(t, "Belgium", notFound.Suggestions[0].Name)
+}
+
+func TestGetCountryDetailsByName_NotFoundWithoutDataset(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Belgum?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return(nil, http_client.ErrUpstream)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryDetailsByName(context.Background(), "Belgum")
+
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+	var notFound *NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Empty(t, notFound.Suggestions)
+}
+
+func TestGetCountryDetailsByName_SuggestionsBackOffAfterFailedLoad(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Belgiun?fullText=true").Return(nil, http_client.ErrNotFound).Times(3)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return(nil, http_client.ErrUpstream).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(allCountriesBody), nil)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ncs := NewCountryService(mockClient, "defaultBaseURL", WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	suggestions := func() []fuzzy.Suggestion {
+		_, err := ncs.GetCountryDetailsByName(ctx, "Belgiun")
+		var notFound *NotFoundError
+		assert.True(t, errors.As(err, &notFound))
+		return notFound.Suggestions
+	}
+
+	assert.Empty(t, suggestions())
+	// The next miss does not retry the failed load.
+	assert.Empty(t, suggestions())
+
+	now = now.Add(namesBackoff)
+	assert.Equal(t, "Belgium", suggestions()[0].Name)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetCountryDetailsByNameFuzzy_ResolvesConfidentMatch(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Swizterland?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(allCountriesBody), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	country, err := ncs.GetCountryDetailsByNameFuzzy(context.Background(), "Swizterland")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CHE", country.CCA3)
+}
+
+func TestGetCountryDetailsByNameFuzzy_NoConfidentMatch(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/Atlantis?fullText=true").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(allCountriesBody), nil)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+
+	_, err := ncs.GetCountryDetailsByNameFuzzy(context.Background(), "Atlantis")
+
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+}
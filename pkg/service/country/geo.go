@@ -0,0 +1,76 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/geo"
+	"fmt"
+)
+
+// Distance returns the great-circle distance and initial bearing between
+// two countries given by code, measured between the coordinates of anchor.
+func (cs *countryService) Distance(ctx context.Context, from, to string, anchor geo.Anchor) (geo.Leg, error) {
+	a, err := cs.geoPoint(ctx, from, anchor)
+	if err != nil {
+		return geo.Leg{}, err
+	}
+	b, err := cs.geoPoint(ctx, to, anchor)
+	if err != nil {
+		return geo.Leg{}, err
+	}
+	return geo.NewLeg(a, b), nil
+}
+
+// NearestCountries returns the k countries whose anchor coordinates are
+// closest to p, nearest first.
+func (cs *countryService) NearestCountries(ctx context.Context, p models.LatLng, k int, anchor geo.Anchor) ([]geo.Hit, error) {
+	if err := geo.Validate(p); err != nil {
+		return nil, err
+	}
+
+	index := cs.countryPoints
+	if anchor == geo.AnchorCapital {
+		index = cs.capitalPoints
+	}
+	ix, err := index.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ix.Nearest(p, k), nil
+}
+
+func (cs *countryService) geoPoint(ctx context.Context, code string, anchor geo.Anchor) (geo.Point, error) {
+	c, err := cs.countryByCode(ctx, code)
+	if err != nil {
+		return geo.Point{}, err
+	}
+	point, ok := geo.PointOf(c, anchor)
+	if !ok {
+		return geo.Point{}, fmt.Errorf("%w: %s", geo.ErrNoCoordinates, c.Name.Common)
+	}
+	return point, nil
+}
+
+// WithBoundaries enables CountryAt with country boundary polygons.
+func WithBoundaries(b *geo.Boundaries) Option {
+	return func(cs *countryService) {
+		cs.boundaries = b
+	}
+}
+
+// CountryAt returns the country whose boundary contains p.
+func (cs *countryService) CountryAt(ctx context.Context, p models.LatLng) (models.CountryDetails, error) {
+	if cs.boundaries == nil {
+		return models.CountryDetails{}, geo.ErrNoBoundaries
+	}
+	if err := geo.Validate(p); err != nil {
+		return models.CountryDetails{}, err
+	}
+
+	code, ok := cs.boundaries.Locate(p)
+	if !ok {
+		return models.CountryDetails{}, fmt.Errorf("%w: no country at %g,%g", http_client.ErrNotFound, p.Lat, p.Lng)
+	}
+	return cs.countryByCode(ctx, code)
+}
@@ -0,0 +1,90 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/geo"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const geoBody = `[
+	{"name": {"common": "India"}, "capital": ["New Delhi"], "capitalInfo": {"latlng": [28.6, 77.2]}, "latlng": [20, 77], "population": 1380004385, "currencies": {"INR": {"symbol": "₹"}}, "cca2": "IN", "cca3": "IND"},
+	{"name": {"common": "Japan"}, "capital": ["Tokyo"], "capitalInfo": {"latlng": [35.68, 139.75]}, "latlng": [36, 138], "population": 125836021, "currencies": {"JPY": {"symbol": "¥"}}, "cca2": "JP", "cca3": "JPN"},
+	{"name": {"common": "Nepal"}, "capital": ["Kathmandu"], "capitalInfo": {"latlng": [27.72, 85.32]}, "latlng": [28, 84], "population": 29136808, "currencies": {"NPR": {"symbol": "₨"}}, "cca2": "NP", "cca3": "NPL"},
+	{"name": {"common": "Bouvet Island"}, "capital": ["None"], "population": 1, "currencies": {"NOK": {"symbol": "kr"}}, "cca2": "BV", "cca3": "BVT"}
+]`
+
+func TestDistance_BetweenCapitals(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(geoBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	leg, err := ncs.Distance(ctx, "IN", "jpn", geo.AnchorCapital)
+	assert.NoError(t, err)
+	assert.Equal(t, "New Delhi", leg.From.Capital)
+	assert.Equal(t, "Tokyo", leg.To.Capital)
+	assert.InDelta(t, 5844, leg.DistanceKm, 5)
+	assert.InDelta(t, 65.2, leg.Bearing, 0.1)
+
+	_, err = ncs.Distance(ctx, "IN", "BV", geo.AnchorCountry)
+	assert.ErrorIs(t, err, geo.ErrNoCoordinates)
+
+	_, err = ncs.Distance(ctx, "IN", "XX", geo.AnchorCountry)
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+}
+
+func TestNearestCountries(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(geoBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	// Gorakhpur lies nearer to Kathmandu than to New Delhi.
+	hits, err := ncs.NearestCountries(ctx, models.LatLng{Lat: 26.76, Lng: 83.37}, 2, geo.AnchorCapital)
+	assert.NoError(t, err)
+	assert.Equal(t, "NPL", hits[0].CCA3)
+	assert.Equal(t, "IND", hits[1].CCA3)
+
+	hits, err = ncs.NearestCountries(ctx, models.LatLng{Lat: 26.76, Lng: 83.37}, 5, geo.AnchorCountry)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 3)
+
+	_, err = ncs.NearestCountries(ctx, models.LatLng{Lat: 100}, 1, geo.AnchorCountry)
+	assert.ErrorIs(t, err, geo.ErrInvalidPoint)
+}
+
+func TestCountryAt(t *testing.T) {
+	boundaries, err := geo.ParseBoundaries([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"ISO_A3": "NPL"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[80, 26.3], [88.2, 26.3], [88.2, 30.5], [80, 30.5], [80, 26.3]]
+		]}},
+		{"type": "Feature", "properties": {"ISO_A3": "SJM"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[10, 76], [30, 76], [30, 81], [10, 81], [10, 76]]
+		]}}
+	]}`))
+	assert.NoError(t, err)
+	ncs, err := NewOfflineCountryService([]byte(geoBody), WithBoundaries(boundaries))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	country, err := ncs.CountryAt(ctx, models.LatLng{Lat: 27.72, Lng: 85.32})
+	assert.NoError(t, err)
+	assert.Equal(t, "Nepal", country.Name.Common)
+
+	_, err = ncs.CountryAt(ctx, models.LatLng{Lat: 0, Lng: 0})
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+
+	// Svalbard has a boundary but is missing from the dataset.
+	_, err = ncs.CountryAt(ctx, models.LatLng{Lat: 78.2, Lng: 15.6})
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+
+	_, err = ncs.CountryAt(ctx, models.LatLng{Lat: 0, Lng: 200})
+	assert.ErrorIs(t, err, geo.ErrInvalidPoint)
+
+	ncs, err = NewOfflineCountryService([]byte(geoBody))
+	assert.NoError(t, err)
+	_, err = ncs.CountryAt(ctx, models.LatLng{Lat: 27.72, Lng: 85.32})
+	assert.ErrorIs(t, err, geo.ErrNoBoundaries)
+}
@@ -0,0 +1,31 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/ipdb"
+	"fmt"
+	"net/netip"
+)
+
+// WithIPDatabase enables CountryByIP with a database mapping addresses to
+// countries.
+func WithIPDatabase(db ipdb.Database) Option {
+	return func(cs *countryService) {
+		cs.ipdb = db
+	}
+}
+
+// CountryByIP returns the full record of the country addr is located in.
+func (cs *countryService) CountryByIP(ctx context.Context, addr netip.Addr) (models.CountryDetails, error) {
+	if cs.ipdb == nil {
+		return models.CountryDetails{}, ipdb.ErrNoDatabase
+	}
+
+	code, ok := cs.ipdb.Lookup(addr)
+	if !ok {
+		return models.CountryDetails{}, fmt.Errorf("%w: no country for %s", http_client.ErrNotFound, addr)
+	}
+	return cs.countryByCode(ctx, code)
+}
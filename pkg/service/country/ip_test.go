@@ -0,0 +1,39 @@
+package country
+
+import (
+	"context"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/ipdb"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountryByIP(t *testing.T) {
+	db, err := ipdb.Parse([]byte("1.0.0.0,1.0.0.255,jp\n2001:218::/32,JP\n5.0.0.0/8,ZZ\n"))
+	assert.NoError(t, err)
+	ncs, err := NewOfflineCountryService([]byte(geoBody), WithIPDatabase(db))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	country, err := ncs.CountryByIP(ctx, netip.MustParseAddr("1.0.0.7"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Japan", country.Name.Common)
+	assert.Equal(t, "Tokyo", country.Capitals[0])
+
+	country, err = ncs.CountryByIP(ctx, netip.MustParseAddr("2001:218::1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "JPN", country.CCA3)
+
+	_, err = ncs.CountryByIP(ctx, netip.MustParseAddr("9.9.9.9"))
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+
+	_, err = ncs.CountryByIP(ctx, netip.MustParseAddr("5.1.1.1"))
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+
+	ncs, err = NewOfflineCountryService([]byte(geoBody))
+	assert.NoError(t, err)
+	_, err = ncs.CountryByIP(ctx, netip.MustParseAddr("1.0.0.7"))
+	assert.ErrorIs(t, err, ipdb.ErrNoDatabase)
+}
@@ -0,0 +1,157 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxPageSize bounds how many countries a single page holds.
+const MaxPageSize = 250
+
+var ErrInvalidList = errors.New("invalid list request")
+
+// ListOptions selects, orders and pages through the full dataset.
+type ListOptions struct {
+	Filter Filter
+	// Sort is "name", "population", "area" or "density"; empty means name.
+	Sort string
+	Desc bool
+	// Limit is the page size, between 1 and MaxPageSize.
+	Limit int
+	// Offset skips that many countries. It is ignored when Cursor is set.
+	Offset int
+	// Cursor continues after the last country of a previous page, as
+	// returned in Page.Next.
+	Cursor string
+}
+
+// Page is one page of a country listing.
+type Page struct {
+	Countries []models.CountryDetails
+	// Total counts every country matching the filter.
+	Total int
+	// Next is the cursor of the following page, empty on the last page.
+	Next string
+}
+
+// cursor marks a position in a listing by the sort key of the last country
+// seen rather than by index, so it keeps pointing at the same place when
+// the dataset is refreshed and countries are added or removed.
+type cursor struct {
+	Sort  string  `json:"s"`
+	Desc  bool    `json:"d,omitempty"`
+	Name  string  `json:"n,omitempty"`
+	Value float64 `json:"v,omitempty"`
+	CCA3  string  `json:"c"`
+}
+
+func (cs *countryService) ListCountries(ctx context.Context, opts ListOptions) (Page, error) {
+	if opts.Sort == "" {
+		opts.Sort = "name"
+	}
+	if _, ok := sortKeys[opts.Sort]; !ok {
+		return Page{}, fmt.Errorf("%w: sort must be name, population, area or density", ErrInvalidList)
+	}
+	if opts.Limit < 1 || opts.Limit > MaxPageSize {
+		return Page{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidList, MaxPageSize)
+	}
+	if opts.Offset < 0 {
+		return Page{}, fmt.Errorf("%w: offset must not be negative", ErrInvalidList)
+	}
+
+	var after *cursor
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor)
+		if err != nil || c.Sort != opts.Sort || c.Desc != opts.Desc {
+			return Page{}, fmt.Errorf("%w: cursor does not belong to this listing", ErrInvalidList)
+		}
+		after = &c
+	}
+
+	all, err := cs.dataset.Countries(ctx)
+	if err != nil {
+		logger.Log().Error("unable to load country dataset:", "error", err)
+		return Page{}, err
+	}
+
+	var countries []models.CountryDetails
+	for _, country := range all {
+		if opts.Filter.Matches(country) {
+			countries = append(countries, country)
+		}
+	}
+	sort.Slice(countries, func(i, j int) bool {
+		return less(countryCursor(countries[i], opts), countryCursor(countries[j], opts))
+	})
+
+	start := opts.Offset
+	if after != nil {
+		start = sort.Search(len(countries), func(i int) bool {
+			return less(*after, countryCursor(countries[i], opts))
+		})
+	}
+	start = min(start, len(countries))
+	end := min(start+opts.Limit, len(countries))
+
+	page := Page{
+		Countries: countries[start:end],
+		Total:     len(countries),
+	}
+	if end < len(countries) {
+		page.Next = encodeCursor(countryCursor(countries[end-1], opts))
+	}
+	return page, nil
+}
+
+// sortKeys maps each numeric sort onto the value it orders by.
+var sortKeys = map[string]func(models.CountryDetails) float64{
+	"name":       nil,
+	"population": func(c models.CountryDetails) float64 { return float64(c.Population) },
+	"area":       func(c models.CountryDetails) float64 { return c.Area },
+	"density":    models.CountryDetails.Density,
+}
+
+func countryCursor(c models.CountryDetails, opts ListOptions) cursor {
+	cur := cursor{Sort: opts.Sort, Desc: opts.Desc, Name: c.Name.Common, CCA3: c.CCA3}
+	if key := sortKeys[opts.Sort]; key != nil {
+		cur.Value = key(c)
+	}
+	return cur
+}
+
+// less orders by the sort key, reversed when descending, then by code and
+// name so that every country has a unique position.
+func less(a, b cursor) bool {
+	if a.Sort != "name" && a.Value != b.Value {
+		return (a.Value < b.Value) != a.Desc
+	}
+	if a.Name != b.Name {
+		if a.Sort == "name" {
+			return (a.Name < b.Name) != a.Desc
+		}
+		return a.Name < b.Name
+	}
+	return strings.Compare(a.CCA3, b.CCA3) < 0
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, err
+	}
+	var c cursor
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
@@ -0,0 +1,105 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const listBody = `[
+	{"name": {"common": "Monaco"}, "capital": ["Monaco"], "population": 39244, "area": 2.02, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "MCO", "region": "Europe"},
+	{"name": {"common": "Malta"}, "capital": ["Valletta"], "population": 525285, "area": 316, "currencies": {"EUR": {"symbol": "€"}}, "cca3": "MLT", "region": "Europe"},
+	{"name": {"common": "Iceland"}, "capital": ["Reykjavik"], "population": 366425, "area": 103000, "currencies": {"ISK": {"symbol": "kr"}}, "cca3": "ISL", "region": "Europe"},
+	{"name": {"common": "Mongolia"}, "capital": ["Ulan Bator"], "population": 3278292, "area": 1564110, "currencies": {"MNT": {"symbol": "₮"}}, "cca3": "MNG", "region": "Asia"},
+	{"name": {"common": "Singapore"}, "capital": ["Singapore"], "population": 5685807, "area": 710, "currencies": {"SGD": {"symbol": "$"}}, "cca3": "SGP", "region": "Asia"}
+]`
+
+func names(p Page) []string {
+	names := []string{}
+	for _, c := range p.Countries {
+		names = append(names, c.Name.Common)
+	}
+	return names
+}
+
+func TestListCountries_SortAndFilter(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(listBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	page, err := ncs.ListCountries(ctx, ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Iceland", "Malta", "Monaco", "Mongolia", "Singapore"}, names(page))
+	assert.Equal(t, 5, page.Total)
+	assert.Empty(t, page.Next)
+
+	page, err = ncs.ListCountries(ctx, ListOptions{Sort: "population", Desc: true, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Singapore", "Mongolia"}, names(page))
+	assert.NotEmpty(t, page.Next)
+
+	page, err = ncs.ListCountries(ctx, ListOptions{Sort: "density", Desc: true, Limit: 2, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Singapore", "Malta"}, names(page)) // Monaco is densest
+
+	page, err = ncs.ListCountries(ctx, ListOptions{Filter: Filter{Region: "europe"}, Sort: "area", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Monaco", "Malta", "Iceland"}, names(page))
+	assert.Equal(t, 3, page.Total)
+}
+
+func TestListCountries_Invalid(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(listBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	for _, opts := range []ListOptions{
+		{Sort: "gdp", Limit: 10},
+		{Limit: 0},
+		{Limit: MaxPageSize + 1},
+		{Limit: 10, Offset: -1},
+		{Limit: 10, Cursor: "garbage"},
+	} {
+		_, err := ncs.ListCountries(ctx, opts)
+		assert.ErrorIs(t, err, ErrInvalidList)
+	}
+
+	page, err := ncs.ListCountries(ctx, ListOptions{Sort: "area", Limit: 1})
+	assert.NoError(t, err)
+	_, err = ncs.ListCountries(ctx, ListOptions{Sort: "population", Limit: 1, Cursor: page.Next})
+	assert.ErrorIs(t, err, ErrInvalidList)
+}
+
+func TestListCountries_CursorStableAcrossRefresh(t *testing.T) {
+	// Bhutan sorts before the cursor and Nepal after it.
+	refreshed := strings.Replace(listBody, "[", `[
+	{"name": {"common": "Bhutan"}, "capital": ["Thimphu"], "population": 771612, "area": 38394, "currencies": {"BTN": {"symbol": "Nu."}}, "cca3": "BTN", "region": "Asia"},
+	{"name": {"common": "Nepal"}, "capital": ["Kathmandu"], "population": 29136808, "area": 147181, "currencies": {"NPR": {"symbol": "₨"}}, "cca3": "NPL", "region": "Asia"},`, 1)
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(listBody), nil).Times(3)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(refreshed), nil).Times(3)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+	ctx := context.Background()
+
+	first, err := ncs.ListCountries(ctx, ListOptions{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Iceland", "Malta"}, names(first))
+
+	_, err = ncs.RefreshDataset(ctx)
+	assert.NoError(t, err)
+
+	second, err := ncs.ListCountries(ctx, ListOptions{Limit: 2, Cursor: first.Next})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Monaco", "Mongolia"}, names(second))
+	assert.Equal(t, 7, second.Total)
+
+	third, err := ncs.ListCountries(ctx, ListOptions{Limit: 2, Cursor: second.Next})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Nepal", "Singapore"}, names(third))
+	assert.Empty(t, third.Next)
+}
@@ -0,0 +1,70 @@
+package country
+
+import (
+	"country-search-api/pkg/models"
+	"sort"
+	"strings"
+)
+
+// matchRank orders partial search results; lower ranks are better matches.
+type matchRank int
+
+const (
+	rankExact matchRank = iota
+	rankPrefix
+	rankSubstring
+	// rankOther covers countries the upstream API matched on a field we do
+	// not compare locally, such as an alternate spelling.
+	rankOther
+)
+
+type match struct {
+	country models.CountryDetails
+	rank    matchRank
+}
+
+// rankMatch returns the best rank of query against any of the given names.
+func rankMatch(query string, names ...string) matchRank {
+	query = strings.ToLower(strings.TrimSpace(query))
+	best := rankOther
+	for _, name := range names {
+		name = strings.ToLower(name)
+		var rank matchRank
+		switch {
+		case name == "":
+			continue
+		case name == query:
+			rank = rankExact
+		case strings.HasPrefix(name, query):
+			rank = rankPrefix
+		case strings.Contains(name, query):
+			rank = rankSubstring
+		default:
+			continue
+		}
+		if rank < best {
+			best = rank
+		}
+	}
+	return best
+}
+
+// sortMatches orders matches by rank and then alphabetically by name.
+func sortMatches(matches []match) []models.CountryDetails {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].country.Name.Common < matches[j].country.Name.Common
+	})
+
+	countries := make([]models.CountryDetails, 0, len(matches))
+	for _, m := range matches {
+		countries = append(countries, m.country)
+	}
+	return countries
+}
+
+func searchCacheKey(query string) string {
+	return "search:" + strings.ToLower(strings.TrimSpace(query))
+}
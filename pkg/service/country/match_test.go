@@ -0,0 +1,25 @@
+package country
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankMatch(t *testing.T) {
+	tests := []struct {
+		query string
+		names []string
+		want  matchRank
+	}{
+		{"guinea", []string{"Guinea"}, rankExact},
+		{"guinea", []string{"Guinea-Bissau"}, rankPrefix},
+		{"guinea", []string{"Papua New Guinea"}, rankSubstring},
+		{"republic", []string{"Guinea", "Republic of Guinea"}, rankPrefix},
+		{"holland", []string{"Netherlands", "Kingdom of the Netherlands"}, rankOther},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rankMatch(tt.query, tt.names...), tt.query)
+	}
+}
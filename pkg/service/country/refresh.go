@@ -0,0 +1,68 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/service/cache"
+	"country-search-api/pkg/service/dataset"
+	"time"
+)
+
+// HistorySize bounds how many dataset updates DatasetHistory keeps.
+const HistorySize = 100
+
+// RefreshDataset reloads the full dataset, logs what changed and records the
+// update in the history. If the reload fails, or loses more than a tenth of
+// the countries, the previous dataset keeps being served. A successful reload
+// purges the local cache, since any cached lookup, search or filter may
+// reflect the previous version.
+func (cs *countryService) RefreshDataset(ctx context.Context) (dataset.Update, error) {
+	update, err := cs.dataset.Refresh(ctx)
+	if err != nil {
+		logger.Log().Error("unable to refresh country dataset, serving previous version:", "error", err)
+		return dataset.Update{}, err
+	}
+
+	logger.Log().Info("refreshed country dataset:", "version", update.Version, "changes", len(update.Changes))
+	for _, change := range update.Changes {
+		logger.Log().Info("country data changed:", "cca3", change.CCA3, "country", change.Name, "kind", change.Kind)
+		for _, f := range change.Fields {
+			logger.Log().Info("country field changed:", "cca3", change.CCA3, "field", f.Field, "old", string(f.Old), "new", string(f.New))
+		}
+	}
+	cache.Cache.Clear()
+
+	cs.history.Add(update)
+	for _, hook := range cs.hooks {
+		hook(update)
+	}
+	return update, nil
+}
+
+// DatasetHistory returns up to limit dataset updates, newest first.
+func (cs *countryService) DatasetHistory(limit int) []dataset.Update {
+	return cs.history.List(limit)
+}
+
+// WithRefreshHook calls hook with every successful dataset refresh. Hooks
+// run synchronously, so slow work should be handed off to a goroutine.
+func WithRefreshHook(hook func(dataset.Update)) Option {
+	return func(cs *countryService) {
+		cs.hooks = append(cs.hooks, hook)
+	}
+}
+
+// RefreshEvery refreshes the dataset of cs every interval until ctx is done.
+func RefreshEvery(ctx context.Context, cs CountryService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = cs.RefreshDataset(ctx)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/service/cache"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/dataset"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRefreshDataset_RecordsChanges(t *testing.T) {
+	before := `[{"name": {"common": "Kazakhstan"}, "capital": ["Astana"], "population": 18754440, "currencies": {"KZT": {"symbol": "₸"}}, "cca2": "KZ", "cca3": "KAZ"}]`
+	after := `[{"name": {"common": "Kazakhstan"}, "capital": ["Nur-Sultan"], "population": 18754440, "currencies": {"KZT": {"symbol": "₸"}}, "cca2": "KZ", "cca3": "KAZ"}]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(before), nil).Times(3)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(after), nil).Times(3)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return(nil, http_client.ErrUpstream).Once()
+	var hooked []dataset.Update
+	ncs := NewCountryService(mockClient, "defaultBaseURL", WithRefreshHook(func(u dataset.Update) {
+		hooked = append(hooked, u)
+	}))
+	ctx := context.Background()
+
+	initial, err := ncs.RefreshDataset(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, initial.Changes)
+
+	update, err := ncs.RefreshDataset(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, update.Changes, 1)
+	assert.Equal(t, "KAZ", update.Changes[0].CCA3)
+	assert.Equal(t, "capitals", update.Changes[0].Fields[0].Field)
+	assert.JSONEq(t, `["Nur-Sultan"]`, string(update.Changes[0].Fields[0].New))
+
+	_, err = ncs.RefreshDataset(ctx)
+	assert.ErrorIs(t, err, http_client.ErrUpstream)
+
+	suggestions, err := ncs.SuggestCountries(ctx, "kaz", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "KAZ", suggestions[0].CCA3)
+
+	history := ncs.DatasetHistory(0)
+	assert.Len(t, history, 2)
+	assert.Equal(t, update.Version, history[0].Version)
+	assert.Equal(t, history, []dataset.Update{hooked[1], hooked[0]})
+	mockClient.AssertExpectations(t)
+}
+
+func TestRefreshDataset_PurgesCachedQueries(t *testing.T) {
+	before := `[
+		{"name": {"common": "Palau"}, "capital": ["Melekeok"], "population": 18092, "currencies": {"USD": {"symbol": "$"}}, "cca2": "PW", "cca3": "PLW", "region": "Oceania", "subregion": "Micronesia"},
+		{"name": {"common": "Nauru"}, "capital": ["Yaren"], "population": 10834, "currencies": {"AUD": {"symbol": "$"}}, "cca2": "NR", "cca3": "NRU", "region": "Oceania", "subregion": "Micronesia"}
+	]`
+	after := `[
+		{"name": {"common": "Palau"}, "capital": ["Ngerulmud"], "population": 18092, "currencies": {"USD": {"symbol": "$"}}, "cca2": "PW", "cca3": "PLW", "region": "Oceania", "subregion": "Micronesia"},
+		{"name": {"common": "Nauru"}, "capital": ["Yaren"], "population": 10834, "currencies": {"AUD": {"symbol": "$"}}, "cca2": "NR", "cca3": "NRU", "region": "Oceania", "subregion": "Micronesia"}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(after), nil)
+	// Until the first refresh the snapshot may be partial, so upstream is
+	// searched too; afterwards the snapshot answers alone.
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/pala").Return(nil, http_client.ErrNotFound).Once()
+	ncs, err := NewHybridCountryService(mockClient, "defaultBaseURL", []byte(before))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	found, err := ncs.SearchCountries(ctx, "pala")
+	assert.NoError(t, err)
+	assert.Equal(t, "Melekeok", found[0].Capitals[0])
+	_, searched := cache.Cache.Get(searchCacheKey("pala"))
+	assert.True(t, searched)
+
+	_, err = ncs.RefreshDataset(ctx)
+	assert.NoError(t, err)
+
+	page, err := ncs.ListCountries(ctx, ListOptions{Filter: Filter{Subregion: "Micronesia"}, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, "Ngerulmud", page.Countries[1].Capitals[0])
+	found, err = ncs.SearchCountries(ctx, "pala")
+	assert.NoError(t, err)
+	assert.Equal(t, "Ngerulmud", found[0].Capitals[0])
+	mockClient.AssertExpectations(t)
+}
+
+func TestHybridSource_AllKeepsSnapshotWhenUpstreamShrinks(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(`[{"name": {"common": "France"}, "cca3": "FRA"}]`), nil)
+	src := newTestHybridSource(t, mockClient)
+
+	countries, err := src.all(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, countries, 5)
+}
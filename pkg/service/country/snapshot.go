@@ -0,0 +1,216 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/dataset"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// NewOfflineCountryService returns a CountryService that never calls the
+// upstream API. snapshot is a REST Countries v3.1 JSON array, such as the
+// body of /all.
+func NewOfflineCountryService(snapshot []byte, opts ...Option) (CountryService, error) {
+	src, err := newSnapshotSource(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return newCountryService(src, opts...), nil
+}
+
+// NewHybridCountryService returns a CountryService that answers from
+// snapshot and falls back to the upstream API for lookups the snapshot
+// cannot answer. Loading the full dataset refreshes the snapshot from
+// upstream, keeping the snapshot when that fails.
+func NewHybridCountryService(httpClient http_client.ClientInf, baseURL string, snapshot []byte, opts ...Option) (CountryService, error) {
+	src, err := newSnapshotSource(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return newCountryService(&hybridSource{
+		snapshot: src,
+		upstream: &upstreamSource{httpClient: httpClient, baseURL: baseURL},
+	}, opts...), nil
+}
+
+// FetchSnapshot downloads the full dataset from the upstream API in the
+// format the offline and hybrid services read.
+func FetchSnapshot(ctx context.Context, httpClient http_client.ClientInf, baseURL string) ([]byte, error) {
+	src := &upstreamSource{httpClient: httpClient, baseURL: baseURL}
+	return src.allRaw(ctx)
+}
+
+// snapshotSource answers lookups from an in-memory copy of the upstream
+// dataset, matching the way the upstream endpoints do. Field selections are
+// ignored since full records are already at hand.
+type snapshotSource struct {
+	mu        sync.RWMutex
+	countries []models.CountryDetails
+}
+
+func newSnapshotSource(snapshot []byte) (*snapshotSource, error) {
+	countries, err := decodeUpstream(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("%w: empty country snapshot", http_client.ErrInvalidData)
+	}
+	return &snapshotSource{countries: countries}, nil
+}
+
+func (s *snapshotSource) byName(_ context.Context, name string, fullText bool, _ []string) ([]models.CountryDetails, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return s.find(func(c models.CountryDetails) bool {
+		for _, n := range searchableNames(c) {
+			n = strings.ToLower(n)
+			if n == name || !fullText && strings.Contains(n, name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *snapshotSource) byCodes(_ context.Context, codes []string, _ []string) ([]models.CountryDetails, error) {
+	return s.find(func(c models.CountryDetails) bool {
+		return slices.ContainsFunc(codes, func(code string) bool { return hasCode(c, code) })
+	})
+}
+
+// hasCode reports whether code is one of c's ISO codes or its IOC code, the
+// codes upstream /alpha lookups match.
+func hasCode(c models.CountryDetails, code string) bool {
+	return slices.Contains(c.Codes(), code) || c.CIOC != "" && strings.EqualFold(c.CIOC, code)
+}
+
+func (s *snapshotSource) all(context.Context) ([]models.CountryDetails, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.countries), nil
+}
+
+func (s *snapshotSource) find(match func(models.CountryDetails) bool) ([]models.CountryDetails, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found []models.CountryDetails
+	for _, c := range s.countries {
+		if match(c) {
+			found = append(found, c)
+		}
+	}
+	if len(found) == 0 {
+		return nil, http_client.ErrNotFound
+	}
+	return found, nil
+}
+
+func (s *snapshotSource) replace(countries []models.CountryDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.countries = countries
+}
+
+// searchableNames lists the common, official and native names upstream
+// /name lookups match against.
+func searchableNames(c models.CountryDetails) []string {
+	names := []string{c.Name.Common, c.Name.Official}
+	for _, n := range c.Name.Native {
+		names = append(names, n.Common, n.Official)
+	}
+	return names
+}
+
+// hybridSource prefers the snapshot and asks upstream for whatever the
+// snapshot cannot answer on its own. Until a refresh has replaced it with
+// the full dataset, the snapshot may be a partial seed, so partial name
+// searches are merged with upstream results rather than trusted as
+// complete.
+type hybridSource struct {
+	snapshot *snapshotSource
+	upstream *upstreamSource
+	// complete is set once the snapshot holds the full upstream dataset.
+	complete atomic.Bool
+}
+
+func (s *hybridSource) byName(ctx context.Context, name string, fullText bool, fields []string) ([]models.CountryDetails, error) {
+	local, err := s.snapshot.byName(ctx, name, fullText, fields)
+	if err == nil && (fullText || s.complete.Load()) {
+		return local, nil
+	}
+	return s.merge(local, err, func() ([]models.CountryDetails, error) {
+		return s.upstream.byName(ctx, name, fullText, fields)
+	})
+}
+
+// byCodes only asks upstream for the codes the snapshot has no country for.
+func (s *hybridSource) byCodes(ctx context.Context, codes []string, fields []string) ([]models.CountryDetails, error) {
+	local, err := s.snapshot.byCodes(ctx, codes, fields)
+	var unresolved []string
+	for _, code := range codes {
+		if !slices.ContainsFunc(local, func(c models.CountryDetails) bool { return hasCode(c, code) }) {
+			unresolved = append(unresolved, code)
+		}
+	}
+	if err == nil && len(unresolved) == 0 {
+		return local, nil
+	}
+	return s.merge(local, err, func() ([]models.CountryDetails, error) {
+		return s.upstream.byCodes(ctx, unresolved, fields)
+	})
+}
+
+// all refreshes the snapshot from upstream so later lookups see current
+// data, and serves the snapshot as it is when upstream is unavailable or
+// returns far fewer countries than the snapshot holds.
+func (s *hybridSource) all(ctx context.Context) ([]models.CountryDetails, error) {
+	countries, err := s.upstream.all(ctx)
+	if err != nil || len(countries) == 0 {
+		logger.Log().Warn("unable to refresh country snapshot, serving it as is:", "error", err)
+		return s.snapshot.all(ctx)
+	}
+
+	local, _ := s.snapshot.all(ctx)
+	if dataset.Shrank(len(local), len(countries)) {
+		logger.Log().Warn("upstream dataset is much smaller than the snapshot, serving it as is:", "snapshot", len(local), "upstream", len(countries))
+		return local, nil
+	}
+
+	s.snapshot.replace(countries)
+	s.complete.Store(true)
+	return countries, nil
+}
+
+// merge adds the countries remote finds to the snapshot's local results,
+// skipping those the snapshot already returned. Upstream errors other than
+// ErrNotFound are returned, since serving the snapshot alone would report
+// countries it lacks as unknown.
+func (s *hybridSource) merge(local []models.CountryDetails, localErr error, remote func() ([]models.CountryDetails, error)) ([]models.CountryDetails, error) {
+	if localErr != nil && !errors.Is(localErr, http_client.ErrNotFound) {
+		return nil, localErr
+	}
+
+	countries, err := remote()
+	if errors.Is(err, http_client.ErrNotFound) && len(local) > 0 {
+		return local, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	merged := local
+	for _, c := range countries {
+		if c.CCA3 == "" || !slices.ContainsFunc(local, func(l models.CountryDetails) bool { return l.CCA3 == c.CCA3 }) {
+			merged = append(merged, c)
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,203 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/snapshot"
+	"country-search-api/pkg/service/stats"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewOfflineCountryService_InvalidSnapshot(t *testing.T) {
+	_, err := NewOfflineCountryService([]byte(`{"not": "an array"}`))
+	assert.ErrorIs(t, err, http_client.ErrInvalidData)
+
+	_, err = NewOfflineCountryService([]byte(`[]`))
+	assert.ErrorIs(t, err, http_client.ErrInvalidData)
+}
+
+func TestOfflineCountryService_Lookups(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(allCountriesBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	country, err := ncs.GetCountryDetailsByName(ctx, "senegal")
+	assert.NoError(t, err)
+	assert.Equal(t, "SEN", country.CCA3)
+
+	countries, err := ncs.SearchCountries(ctx, "an")
+	assert.NoError(t, err)
+	assert.Equal(t, "France", countries[0].Name.Common)
+	assert.Len(t, countries, 2) // Antarctica fails validation
+
+	lookup, err := ncs.GetCountriesByCodes(ctx, []string{"756", "be", "ZZ"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Switzerland", lookup.Countries[0].Name.Common)
+	assert.Equal(t, "Belgium", lookup.Countries[1].Name.Common)
+	assert.Equal(t, []string{"ZZ"}, lookup.Missing)
+
+	page, err := ncs.ListCountries(ctx, ListOptions{Filter: Filter{Currency: "xof"}, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	page, err = ncs.ListCountries(ctx, ListOptions{Filter: Filter{Subregion: "western europe", Language: "Swiss German"}, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	suggestions, err := ncs.SuggestCountries(ctx, "bel", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, "BEL", suggestions[0].CCA3)
+
+	_, err = ncs.GetCountryDetailsByName(ctx, "Senegall")
+	var notFound *NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "Senegal", notFound.Suggestions[0].Name)
+}
+
+func TestOfflineCountryService_EmbeddedSnapshot(t *testing.T) {
+	ncs, err := NewOfflineCountryService(snapshot.Embedded())
+	assert.NoError(t, err)
+
+	country, err := ncs.GetCountryDetailsByName(context.Background(), "Burma")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "MMR", country.CCA3)
+	assert.Equal(t, "alias", country.ResolvedFrom.Via)
+}
+
+func newTestHybridSource(t *testing.T, mockClient *mock_http_client.MockClientInf) *hybridSource {
+	local, err := newSnapshotSource([]byte(allCountriesBody))
+	assert.NoError(t, err)
+	return &hybridSource{snapshot: local, upstream: &upstreamSource{httpClient: mockClient, baseURL: "defaultBaseURL"}}
+}
+
+func TestHybridSource_PrefersSnapshot(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	src := newTestHybridSource(t, mockClient)
+	ctx := context.Background()
+
+	countries, err := src.byName(ctx, "senegal", true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "SEN", countries[0].CCA3)
+
+	countries, err = src.byCodes(ctx, []string{"SEN", "BE"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, countries, 2)
+
+	// Once the snapshot holds the full dataset, partial answers are complete.
+	src.complete.Store(true)
+	countries, err = src.byName(ctx, "an", false, nil)
+	assert.NoError(t, err)
+	assert.Len(t, countries, 3)
+
+	mockClient.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+}
+
+func TestHybridSource_AsksUpstreamForCodesSnapshotLacks(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	body := `[{"name": {"common": "Ghana"}, "capital": ["Accra"], "population": 31072945, "currencies": {"GHS": {"symbol": "₵"}}, "cca2": "GH", "cca3": "GHA"}]`
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/alpha?codes=GHA%2CZZ").Return([]byte(body), nil).Once()
+	src := newTestHybridSource(t, mockClient)
+
+	countries, err := src.byCodes(context.Background(), []string{"SEN", "GHA", "ZZ"}, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, countries, 2)
+	assert.Equal(t, "SEN", countries[0].CCA3)
+	assert.Equal(t, "GHA", countries[1].CCA3)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHybridSource_MergesPartialResults(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	names := `[
+		{"name": {"common": "France"}, "cca3": "FRA"},
+		{"name": {"common": "Andorra"}, "cca3": "AND"}
+	]`
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/fran").Return([]byte(names), nil).Once()
+	src := newTestHybridSource(t, mockClient)
+	ctx := context.Background()
+
+	countries, err := src.byName(ctx, "fran", false, nil)
+	assert.NoError(t, err)
+	assert.Len(t, countries, 2)
+	assert.Equal(t, "FRA", countries[0].CCA3)
+	assert.Equal(t, "AND", countries[1].CCA3)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHybridSource_ReportsUpstreamErrors(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/alpha?codes=GHA").Return(nil, http_client.ErrUpstream).Once()
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/sene").Return(nil, http_client.ErrNotFound).Once()
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/name/atlantis").Return(nil, http_client.ErrNotFound).Once()
+	src := newTestHybridSource(t, mockClient)
+	ctx := context.Background()
+
+	_, err := src.byCodes(ctx, []string{"SEN", "GHA"}, nil)
+	assert.ErrorIs(t, err, http_client.ErrUpstream)
+
+	// Upstream not knowing a name leaves the snapshot's matches.
+	countries, err := src.byName(ctx, "sene", false, nil)
+	assert.NoError(t, err)
+	assert.Len(t, countries, 1)
+
+	_, err = src.byName(ctx, "atlantis", false, nil)
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHybridCountryService_FallsBackToUpstream(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	body := `[{"name": {"common": "Ghana"}, "capital": ["Accra"], "population": 31072945, "currencies": {"GHS": {"symbol": "₵"}}, "cca2": "GH", "cca3": "GHA"}]`
+	mockClient.On("Get", mock.Anything, "defaultBaseURL/alpha?codes=GHA").Return([]byte(body), nil).Once()
+	ncs, err := NewHybridCountryService(mockClient, "defaultBaseURL", []byte(allCountriesBody))
+	assert.NoError(t, err)
+
+	country, err := ncs.GetCountryByCode(context.Background(), "GHA")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ghana", country.Name.Common)
+	mockClient.AssertExpectations(t)
+}
+
+func TestHybridSource_AllKeepsSnapshotWhenRefreshFails(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return(nil, http_client.ErrUpstream).Once()
+	src := newTestHybridSource(t, mockClient)
+
+	countries, err := src.all(context.Background())
+
+	assert.NoError(t, err)
+	// The dataset keeps Antarctica although it fails summary validation.
+	assert.Len(t, countries, 5)
+	assert.Equal(t, "Antarctica", countries[4].Name.Common)
+	mockClient.AssertExpectations(t)
+}
+
+func TestOfflineService_DatasetKeepsTerritories(t *testing.T) {
+	ncs, err := NewOfflineCountryService([]byte(allCountriesBody))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	page, err := ncs.ListCountries(ctx, ListOptions{Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, page.Total)
+	assert.Equal(t, "Antarctica", page.Countries[0].Name.Common)
+
+	report, err := ncs.Stats(ctx, stats.ByRegion)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.World.Countries)
+	assert.Equal(t, "Antarctic", report.Groups[2].Name)
+	assert.Equal(t, int64(1000), report.Groups[2].Population)
+
+	// Name lookups still answer with the summary view, which needs a
+	// capital and a currency.
+	_, err = ncs.GetCountryByName(ctx, "Antarctica")
+	assert.ErrorIs(t, err, http_client.ErrInvalidData)
+}
@@ -0,0 +1,78 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// source answers the raw lookups a CountryService is built on, either from
+// the REST Countries API or from an offline snapshot. Results are not
+// validated, and a lookup without matches fails with http_client.ErrNotFound.
+type source interface {
+	// byName matches the common or official name exactly when fullText is
+	// set and partially otherwise.
+	byName(ctx context.Context, name string, fullText bool, fields []string) ([]models.CountryDetails, error)
+	byCodes(ctx context.Context, codes []string, fields []string) ([]models.CountryDetails, error)
+	// all returns the full dataset, including territories such as
+	// Antarctica that lack what the summary view requires.
+	all(ctx context.Context) ([]models.CountryDetails, error)
+}
+
+// upstreamSource queries the REST Countries v3.1 API.
+type upstreamSource struct {
+	httpClient http_client.ClientInf
+	baseURL    string
+}
+
+func (s *upstreamSource) byName(ctx context.Context, name string, fullText bool, fields []string) ([]models.CountryDetails, error) {
+	logger.Log().Info("searching in 3rd party API:", "country", name)
+
+	escaped := url.PathEscape(name)
+	endpoint := fmt.Sprintf(
+		"%s/name/%s",
+		s.baseURL,
+		escaped,
+	)
+
+	var query []string
+	if fullText {
+		query = append(query, "fullText=true")
+	}
+	if len(fields) > 0 {
+		query = append(query, "fields="+strings.Join(mapFields(fields), ","))
+	}
+	if len(query) > 0 {
+		endpoint += "?" + strings.Join(query, "&")
+	}
+
+	return s.get(ctx, endpoint)
+}
+
+func (s *upstreamSource) byCodes(ctx context.Context, codes []string, fields []string) ([]models.CountryDetails, error) {
+	logger.Log().Info("searching in 3rd party API:", "codes", codes)
+
+	endpoint := fmt.Sprintf(
+		"%s/alpha?codes=%s",
+		s.baseURL,
+		url.QueryEscape(strings.Join(codes, ",")),
+	)
+	if len(fields) > 0 {
+		endpoint += "&fields=" + strings.Join(mapFields(fields), ",")
+	}
+
+	return s.get(ctx, endpoint)
+}
+
+func (s *upstreamSource) get(ctx context.Context, endpoint string) ([]models.CountryDetails, error) {
+	body, err := s.httpClient.Get(ctx, endpoint)
+	if err != nil {
+		logger.Log().Error("unable to get country details from 3rd party API:", "endpoint", endpoint, "error", err)
+		return nil, err
+	}
+	return decodeUpstream(body)
+}
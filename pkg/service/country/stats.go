@@ -0,0 +1,25 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/service/stats"
+)
+
+// Stats aggregates the full dataset by region, subregion or continent. The
+// aggregation is computed once per dataset version.
+func (cs *countryService) Stats(ctx context.Context, by stats.Dimension) (stats.Report, error) {
+	summary, err := cs.stats.Get(ctx)
+	if err != nil {
+		return stats.Report{}, err
+	}
+	return summary.Report(by), nil
+}
+
+// TopCountries ranks the countries of the full dataset by a numeric field.
+func (cs *countryService) TopCountries(ctx context.Context, q stats.TopQuery) ([]stats.Ranked, error) {
+	summary, err := cs.stats.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return summary.Top(q)
+}
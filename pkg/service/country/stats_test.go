@@ -0,0 +1,50 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/service/stats"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStats_RecomputedAfterRefresh(t *testing.T) {
+	before := `[
+		{"name": {"common": "Uzbekistan"}, "capital": ["Tashkent"], "region": "Asia", "subregion": "Central Asia", "continents": ["Asia"], "population": 34232050, "area": 447400, "currencies": {"UZS": {"symbol": "so'm"}}, "cca2": "UZ", "cca3": "UZB"},
+		{"name": {"common": "Tajikistan"}, "capital": ["Dushanbe"], "region": "Asia", "subregion": "Central Asia", "continents": ["Asia"], "population": 9537642, "area": 143100, "currencies": {"TJS": {"symbol": "SM"}}, "cca2": "TJ", "cca3": "TJK"}
+	]`
+	after := `[
+		{"name": {"common": "Uzbekistan"}, "capital": ["Tashkent"], "region": "Asia", "subregion": "Central Asia", "continents": ["Asia"], "population": 36000000, "area": 447400, "currencies": {"UZS": {"symbol": "so'm"}}, "cca2": "UZ", "cca3": "UZB"},
+		{"name": {"common": "Tajikistan"}, "capital": ["Dushanbe"], "region": "Asia", "subregion": "Central Asia", "continents": ["Asia"], "population": 9537642, "area": 143100, "currencies": {"TJS": {"symbol": "SM"}}, "cca2": "TJ", "cca3": "TJK"}
+	]`
+
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(before), nil).Times(3)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(isAllEndpoint)).Return([]byte(after), nil).Times(3)
+	ncs := NewCountryService(mockClient, "defaultBaseURL")
+	ctx := context.Background()
+
+	report, err := ncs.Stats(ctx, stats.BySubregion)
+	assert.NoError(t, err)
+	assert.Equal(t, "Central Asia", report.Groups[0].Name)
+	assert.Equal(t, int64(43769692), report.Groups[0].Population)
+	assert.Equal(t, "TJK", report.Groups[0].LeastPopulous.CCA3)
+
+	// Served from the cached aggregation: no further upstream calls.
+	top, err := ncs.TopCountries(ctx, stats.TopQuery{Field: "density", N: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "UZB", top[0].CCA3)
+
+	_, err = ncs.RefreshDataset(ctx)
+	assert.NoError(t, err)
+
+	report, err = ncs.Stats(ctx, stats.ByRegion)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(45537642), report.World.Population)
+
+	_, err = ncs.TopCountries(ctx, stats.TopQuery{Field: "gdp"})
+	assert.ErrorIs(t, err, stats.ErrInvalidQuery)
+	mockClient.AssertExpectations(t)
+}
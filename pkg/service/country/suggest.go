@@ -0,0 +1,16 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/service/autocomplete"
+)
+
+// SuggestCountries returns typeahead suggestions for prefix from the
+// in-memory prefix index; it never calls upstream once the dataset is loaded.
+func (cs *countryService) SuggestCountries(ctx context.Context, prefix string, limit int) ([]autocomplete.Suggestion, error) {
+	trie, err := cs.prefixes.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return trie.Suggest(prefix, limit), nil
+}
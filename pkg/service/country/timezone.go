@@ -0,0 +1,44 @@
+package country
+
+import (
+	"context"
+	"country-search-api/pkg/service/timezone"
+	"fmt"
+	"time"
+)
+
+// WithClock replaces time.Now as the source of the current time.
+func WithClock(now func() time.Time) Option {
+	return func(cs *countryService) {
+		cs.now = now
+	}
+}
+
+// LocalTime returns the current local time in the capital and in each
+// timezone of the country with the given code.
+func (cs *countryService) LocalTime(ctx context.Context, code string) (timezone.CountryTime, error) {
+	country, err := cs.countryByCode(ctx, code)
+	if err != nil {
+		return timezone.CountryTime{}, err
+	}
+	return timezone.Now(country, cs.now()), nil
+}
+
+// BusinessOverlap returns when, over the coming week, business hours in the
+// capitals of two countries overlap.
+func (cs *countryService) BusinessOverlap(ctx context.Context, from, to string, hours timezone.BusinessHours) (timezone.Overlap, error) {
+	a, err := cs.countryByCode(ctx, from)
+	if err != nil {
+		return timezone.Overlap{}, err
+	}
+	b, err := cs.countryByCode(ctx, to)
+	if err != nil {
+		return timezone.Overlap{}, err
+	}
+
+	overlap, ok := timezone.BusinessOverlap(a, b, hours, cs.now())
+	if !ok {
+		return timezone.Overlap{}, fmt.Errorf("%w: %s or %s", timezone.ErrNoTimezone, a.Name.Common, b.Name.Common)
+	}
+	return overlap, nil
+}
@@ -0,0 +1,57 @@
+package country
+
+import (
+	"context"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/timezone"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const timeBody = `[
+	{"name": {"common": "Chile"}, "capital": ["Santiago"], "capitalInfo": {"latlng": [-33.45, -70.67]}, "population": 19116209, "currencies": {"CLP": {"symbol": "$"}}, "timezones": ["UTC-06:00", "UTC-04:00"], "cca2": "CL", "cca3": "CHL"},
+	{"name": {"common": "Germany"}, "capital": ["Berlin"], "capitalInfo": {"latlng": [52.52, 13.4]}, "population": 83240525, "currencies": {"EUR": {"symbol": "€"}}, "timezones": ["UTC+01:00"], "cca2": "DE", "cca3": "DEU"},
+	{"name": {"common": "Antarctica"}, "capital": ["None"], "population": 1000, "currencies": {"USD": {"symbol": "$"}}, "cca2": "AQ", "cca3": "ATA"}
+]`
+
+func TestLocalTime(t *testing.T) {
+	now := time.Date(2026, time.January, 14, 12, 0, 0, 0, time.UTC)
+	ncs, err := NewOfflineCountryService([]byte(timeBody), WithClock(func() time.Time { return now }))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	local, err := ncs.LocalTime(ctx, "chl")
+	assert.NoError(t, err)
+	assert.Equal(t, "Santiago", local.Capital)
+	assert.Equal(t, "America/Santiago", local.CapitalTime.Zone)
+	assert.Equal(t, "2026-01-14T09:00:00-03:00", local.CapitalTime.Time.Format(time.RFC3339))
+	assert.True(t, local.CapitalTime.DST)
+	assert.Len(t, local.Timezones, 2)
+	assert.Equal(t, "Pacific/Easter", local.Timezones[0].Zone)
+
+	_, err = ncs.LocalTime(ctx, "XX")
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+}
+
+func TestBusinessOverlap_Service(t *testing.T) {
+	now := time.Date(2026, time.January, 14, 12, 0, 0, 0, time.UTC)
+	ncs, err := NewOfflineCountryService([]byte(timeBody), WithClock(func() time.Time { return now }))
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	overlap, err := ncs.BusinessOverlap(ctx, "DE", "CL", timezone.DefaultBusinessHours)
+	assert.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", overlap.From.Zone)
+	// Berlin works 08:00-16:00 UTC and Santiago 12:00-20:00 UTC in January.
+	assert.Equal(t, time.Date(2026, time.January, 14, 12, 0, 0, 0, time.UTC), overlap.Windows[0].Start)
+	assert.Equal(t, 240, overlap.Windows[0].Minutes)
+	assert.Len(t, overlap.Windows, 5)
+
+	_, err = ncs.BusinessOverlap(ctx, "DE", "ATA", timezone.DefaultBusinessHours)
+	assert.ErrorIs(t, err, timezone.ErrNoTimezone)
+
+	_, err = ncs.BusinessOverlap(ctx, "DE", "XX", timezone.DefaultBusinessHours)
+	assert.ErrorIs(t, err, http_client.ErrNotFound)
+}
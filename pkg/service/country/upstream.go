@@ -0,0 +1,320 @@
+package country
+
+import (
+	"bytes"
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/money"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// detailFields are the upstream fields mapped into models.CountryDetails.
+var detailFields = []string{
+	"name", "cca2", "cca3", "ccn3", "cioc", "altSpellings", "capital", "capitalInfo",
+	"currencies", "languages", "region", "subregion", "continents", "borders", "area",
+	"latlng", "landlocked", "population", "timezones", "idd", "tld", "flag", "flags",
+	"car", "unMember", "translations",
+}
+
+// upstreamFields maps each models.CountryDetails JSON field to the upstream
+// fields it is decoded from.
+var upstreamFields = map[string][]string{
+	"name":          {"name"},
+	"cca2":          {"cca2"},
+	"cca3":          {"cca3"},
+	"ccn3":          {"ccn3"},
+	"cioc":          {"cioc"},
+	"altSpellings":  {"altSpellings"},
+	"translations":  {"translations"},
+	"capitals":      {"capital"},
+	"capitalLatLng": {"capitalInfo"},
+	"currencies":    {"currencies"},
+	"languages":     {"languages"},
+	"region":        {"region"},
+	"subregion":     {"subregion"},
+	"continents":    {"continents"},
+	"borders":       {"borders"},
+	"area":          {"area"},
+	"latlng":        {"latlng"},
+	"landlocked":    {"landlocked"},
+	"population":    {"population"},
+	"timezones":     {"timezones"},
+	"callingCodes":  {"idd"},
+	"tlds":          {"tld"},
+	"flag":          {"flag", "flags"},
+	"carSide":       {"car"},
+	"unMember":      {"unMember"},
+}
+
+// maxFieldsPerRequest is the upstream limit on the fields parameter of /all.
+const maxFieldsPerRequest = 10
+
+// upstreamCountry mirrors a REST Countries v3.1 record.
+type upstreamCountry struct {
+	Name struct {
+		Common     string                       `json:"common"`
+		Official   string                       `json:"official"`
+		NativeName map[string]models.NativeName `json:"nativeName"`
+	} `json:"name"`
+	TLD        []string           `json:"tld"`
+	CCA2       string             `json:"cca2"`
+	CCN3       string             `json:"ccn3"`
+	CCA3       string             `json:"cca3"`
+	CIOC       string             `json:"cioc"`
+	UNMember   bool               `json:"unMember"`
+	Currencies upstreamCurrencies `json:"currencies"`
+	IDD        struct {
+		Root     string   `json:"root"`
+		Suffixes []string `json:"suffixes"`
+	} `json:"idd"`
+	Capital     []string `json:"capital"`
+	CapitalInfo struct {
+		LatLng []float64 `json:"latlng"`
+	} `json:"capitalInfo"`
+	AltSpellings []string                     `json:"altSpellings"`
+	Translations map[string]models.NativeName `json:"translations"`
+	Region       string                       `json:"region"`
+	Subregion    string                       `json:"subregion"`
+	Languages    map[string]string            `json:"languages"`
+	LatLng       []float64                    `json:"latlng"`
+	Landlocked   bool                         `json:"landlocked"`
+	Borders      []string                     `json:"borders"`
+	Area         float64                      `json:"area"`
+	Flag         string                       `json:"flag"`
+	Flags        struct {
+		PNG string `json:"png"`
+		SVG string `json:"svg"`
+		Alt string `json:"alt"`
+	} `json:"flags"`
+	Population int64 `json:"population"`
+	Car        struct {
+		Side string `json:"side"`
+	} `json:"car"`
+	Timezones  []string `json:"timezones"`
+	Continents []string `json:"continents"`
+}
+
+// upstreamCurrencies is the upstream currencies object in document order.
+// Upstream lists a country's primary currency first, which the summary view
+// reports, so the order must survive decoding.
+type upstreamCurrencies []upstreamCurrency
+
+type upstreamCurrency struct {
+	Code   string `json:"-"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+func (c *upstreamCurrencies) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		*c = nil
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("currencies: want an object, got %v", tok)
+	}
+
+	var currencies upstreamCurrencies
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var currency upstreamCurrency
+		if err := dec.Decode(&currency); err != nil {
+			return err
+		}
+		currency.Code = tok.(string)
+		currencies = append(currencies, currency)
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	*c = currencies
+	return nil
+}
+
+func (u upstreamCountry) toDetails() models.CountryDetails {
+	d := models.CountryDetails{
+		Name: models.CountryName{
+			Common:   u.Name.Common,
+			Official: u.Name.Official,
+			Native:   u.Name.NativeName,
+		},
+		CCA2:          strings.ToUpper(u.CCA2),
+		CCA3:          strings.ToUpper(u.CCA3),
+		CCN3:          u.CCN3,
+		CIOC:          u.CIOC,
+		AltSpellings:  u.AltSpellings,
+		Translations:  u.Translations,
+		Capitals:      nonNil(u.Capital),
+		CapitalLatLng: toLatLng(u.CapitalInfo.LatLng),
+		Region:        u.Region,
+		Subregion:     u.Subregion,
+		Continents:    nonNil(u.Continents),
+		Borders:       nonNil(u.Borders),
+		Area:          u.Area,
+		LatLng:        toLatLng(u.LatLng),
+		Landlocked:    u.Landlocked,
+		Population:    u.Population,
+		Timezones:     nonNil(u.Timezones),
+		CallingCodes:  callingCodes(u.IDD.Root, u.IDD.Suffixes),
+		TLDs:          nonNil(u.TLD),
+		Flag: models.Flag{
+			Emoji: u.Flag,
+			PNG:   u.Flags.PNG,
+			SVG:   u.Flags.SVG,
+			Alt:   u.Flags.Alt,
+		},
+		CarSide:  u.Car.Side,
+		UNMember: u.UNMember,
+	}
+
+	d.Currencies = make([]models.Currency, 0, len(u.Currencies))
+	for _, c := range u.Currencies {
+		d.Currencies = append(d.Currencies, models.Currency{Code: c.Code, Name: c.Name, Symbol: c.Symbol, MinorUnits: money.MinorUnits(c.Code)})
+	}
+
+	d.Languages = make([]models.Language, 0, len(u.Languages))
+	for code, name := range u.Languages {
+		d.Languages = append(d.Languages, models.Language{Code: code, Name: name})
+	}
+	sort.Slice(d.Languages, func(i, j int) bool { return d.Languages[i].Code < d.Languages[j].Code })
+
+	return d
+}
+
+// callingCodes joins the IDD root with its suffixes. Countries with many
+// suffixes, such as the NANP members under +1, are reported by root alone.
+func callingCodes(root string, suffixes []string) []string {
+	switch {
+	case root == "":
+		return []string{}
+	case len(suffixes) == 1:
+		return []string{root + suffixes[0]}
+	default:
+		return []string{root}
+	}
+}
+
+func toLatLng(v []float64) *models.LatLng {
+	if len(v) != 2 {
+		return nil
+	}
+	return &models.LatLng{Lat: v[0], Lng: v[1]}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// validOnly drops the records that fail validation. Only lookups answered
+// with the summary view apply it; the dataset keeps every record.
+func validOnly(all []models.CountryDetails) []models.CountryDetails {
+	countries := make([]models.CountryDetails, 0, len(all))
+	for _, d := range all {
+		if d.Validate() {
+			countries = append(countries, d)
+		}
+	}
+	return countries
+}
+
+// decodeUpstream maps an upstream JSON array onto CountryDetails as is.
+func decodeUpstream(body []byte) ([]models.CountryDetails, error) {
+	var upstream []upstreamCountry
+	if err := json.Unmarshal(body, &upstream); err != nil {
+		return nil, fmt.Errorf("%w: %v", http_client.ErrInvalidData, err)
+	}
+
+	countries := make([]models.CountryDetails, 0, len(upstream))
+	for _, u := range upstream {
+		countries = append(countries, u.toDetails())
+	}
+	return countries, nil
+}
+
+// all loads every country from the upstream /all endpoint.
+func (s *upstreamSource) all(ctx context.Context) ([]models.CountryDetails, error) {
+	logger.Log().Info("loading full country dataset from 3rd party API")
+
+	body, err := s.allRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return decodeUpstream(body)
+}
+
+// allRaw returns the upstream /all response as a single JSON array. Since
+// /all caps the number of fields per request, the fields are requested in
+// chunks keyed by cca3 and merged.
+func (s *upstreamSource) allRaw(ctx context.Context) ([]byte, error) {
+	merged := make(map[string]map[string]json.RawMessage)
+	var order []string
+
+	for _, fields := range fieldChunks(detailFields, maxFieldsPerRequest) {
+		endpoint := fmt.Sprintf("%s/all?fields=%s", s.baseURL, strings.Join(fields, ","))
+		body, err := s.httpClient.Get(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		var chunk []map[string]json.RawMessage
+		if err := json.Unmarshal(body, &chunk); err != nil {
+			return nil, fmt.Errorf("%w: %v", http_client.ErrInvalidData, err)
+		}
+
+		for _, record := range chunk {
+			var cca3 string
+			if err := json.Unmarshal(record["cca3"], &cca3); err != nil || cca3 == "" {
+				continue
+			}
+			existing, ok := merged[cca3]
+			if !ok {
+				existing = make(map[string]json.RawMessage)
+				merged[cca3] = existing
+				order = append(order, cca3)
+			}
+			for k, v := range record {
+				existing[k] = v
+			}
+		}
+	}
+
+	records := make([]map[string]json.RawMessage, 0, len(order))
+	for _, cca3 := range order {
+		records = append(records, merged[cca3])
+	}
+	return json.Marshal(records)
+}
+
+// fieldChunks splits fields into groups of at most size, each led by cca3 so
+// that the responses can be joined.
+func fieldChunks(fields []string, size int) [][]string {
+	var chunks [][]string
+	current := []string{"cca3"}
+	for _, f := range fields {
+		if f == "cca3" {
+			continue
+		}
+		if len(current) == size {
+			chunks = append(chunks, current)
+			current = []string{"cca3"}
+		}
+		current = append(current, f)
+	}
+	return append(chunks, current)
+}
@@ -0,0 +1,111 @@
+package country
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/models"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDecodeCountries_RichRecord(t *testing.T) {
+	body := `[{
+		"name": {"common": "South Africa", "official": "Republic of South Africa", "nativeName": {"zul": {"official": "IRiphabliki yaseNingizimu Afrika", "common": "Ningizimu Afrika"}}},
+		"tld": [".za"],
+		"cca2": "ZA", "ccn3": "710", "cca3": "ZAF", "cioc": "RSA",
+		"unMember": true,
+		"currencies": {"ZAR": {"name": "South African rand", "symbol": "R"}},
+		"idd": {"root": "+2", "suffixes": ["7"]},
+		"capital": ["Pretoria", "Bloemfontein", "Cape Town"],
+		"capitalInfo": {"latlng": [-25.7, 28.22]},
+		"altSpellings": ["ZA", "RSA"],
+		"region": "Africa", "subregion": "Southern Africa",
+		"languages": {"afr": "Afrikaans", "eng": "English", "zul": "Zulu"},
+		"latlng": [-29.0, 24.0],
+		"landlocked": false,
+		"borders": ["BWA", "LSO", "MOZ", "NAM", "SWZ", "ZWE"],
+		"area": 1221037,
+		"flag": "🇿🇦",
+		"flags": {"png": "https://flagcdn.com/w320/za.png", "svg": "https://flagcdn.com/za.svg"},
+		"population": 59308690,
+		"car": {"signs": ["ZA"], "side": "left"},
+		"timezones": ["UTC+02:00"],
+		"continents": ["Africa"]
+	}]`
+
+	countries, err := decodeUpstream([]byte(body))
+
+	assert.NoError(t, err)
+	assert.Len(t, countries, 1)
+	za := countries[0]
+	assert.Equal(t, []string{"Pretoria", "Bloemfontein", "Cape Town"}, za.Capitals)
+	assert.Equal(t, []models.Currency{{Code: "ZAR", Name: "South African rand", Symbol: "R", MinorUnits: 2}}, za.Currencies)
+	assert.Equal(t, "Zulu", za.Languages[2].Name)
+	assert.Equal(t, []string{"+27"}, za.CallingCodes)
+	assert.Equal(t, &models.LatLng{Lat: -25.7, Lng: 28.22}, za.CapitalLatLng)
+	assert.Equal(t, "Ningizimu Afrika", za.Name.Native["zul"].Common)
+	assert.Equal(t, []string{"ZA", "ZAF", "710"}, za.Codes())
+	assert.Equal(t, "left", za.CarSide)
+	assert.True(t, za.UNMember)
+	assert.Equal(t, models.Country{Name: "South Africa", Capital: "Pretoria", Currency: "R", CurrencyCode: "ZAR", Population: 59308690}, za.Summary())
+}
+
+func TestDecodeCountries_MultipleCurrenciesInUpstreamOrder(t *testing.T) {
+	body := `[{"name": {"common": "Panama"}, "capital": ["Panama City"], "population": 4314768, "currencies": {"PAB": {"name": "Panamanian balboa", "symbol": "B/."}, "USD": {"name": "United States dollar", "symbol": "$"}}},
+		{"name": {"common": "Zimbabwe"}, "capital": ["Harare"], "population": 14862927, "currencies": {"USD": {"name": "United States dollar", "symbol": "$"}, "BWP": {"name": "Botswana pula", "symbol": "P"}}},
+		{"name": {"common": "Antarctica"}, "currencies": null}]`
+
+	countries, err := decodeUpstream([]byte(body))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PAB", countries[0].Currencies[0].Code)
+	assert.Equal(t, "USD", countries[0].Currencies[1].Code)
+	assert.Equal(t, "B/.", countries[0].Summary().Currency)
+	// The primary currency is upstream's first, not the first by code.
+	assert.Equal(t, "USD", countries[1].Currencies[0].Code)
+	assert.Equal(t, "$", countries[1].Summary().Currency)
+	assert.Empty(t, countries[2].Currencies)
+
+	_, err = decodeUpstream([]byte(`[{"name": {"common": "Panama"}, "currencies": ["PAB"]}]`))
+	assert.Error(t, err)
+}
+
+func TestCallingCodes(t *testing.T) {
+	assert.Equal(t, []string{"+91"}, callingCodes("+9", []string{"1"}))
+	assert.Equal(t, []string{"+1"}, callingCodes("+1", []string{"201", "202"}))
+	assert.Equal(t, []string{}, callingCodes("", nil))
+}
+
+func TestFieldChunks(t *testing.T) {
+	chunks := fieldChunks(detailFields, maxFieldsPerRequest)
+
+	seen := map[string]bool{}
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), maxFieldsPerRequest)
+		assert.Equal(t, "cca3", chunk[0])
+		for _, f := range chunk[1:] {
+			seen[f] = true
+		}
+	}
+	assert.Len(t, seen, len(detailFields)-1)
+}
+
+func TestUpstreamSourceAll_MergesFieldChunks(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", mock.Anything, mock.MatchedBy(func(endpoint string) bool {
+		return strings.Contains(endpoint, "fields=cca3,name,")
+	})).Return([]byte(`[{"cca3": "ISL", "name": {"common": "Iceland"}, "capital": ["Reykjavik"]}]`), nil).Once()
+	mockClient.On("Get", mock.Anything, mock.Anything).
+		Return([]byte(`[{"cca3": "ISL", "currencies": {"ISK": {"name": "Icelandic króna", "symbol": "kr"}}, "population": 366425, "borders": []}]`), nil)
+
+	cs := NewCountryService(mockClient, "defaultBaseURL").(*countryService)
+
+	countries, err := cs.src.all(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, countries, 1)
+	assert.Equal(t, models.Country{Name: "Iceland", Capital: "Reykjavik", Currency: "kr", CurrencyCode: "ISK", Population: 366425}, countries[0].Summary())
+}
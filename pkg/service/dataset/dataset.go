@@ -0,0 +1,175 @@
+package dataset
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrEmptyDataset is returned by Refresh when the loader yields no
+	// countries.
+	ErrEmptyDataset = errors.New("empty country dataset")
+	// ErrShrunkDataset is returned by Refresh when the loader yields far
+	// fewer countries than the dataset being served, which points to a
+	// truncated upstream response rather than countries ceasing to exist.
+	ErrShrunkDataset = errors.New("country dataset shrank")
+)
+
+// minRefreshRatio is the share of the current countries a refreshed dataset
+// must keep to be swapped in.
+const minRefreshRatio = 0.9
+
+// Shrank reports whether a dataset of next countries replacing one of prev
+// countries lost too many of them to be trusted.
+func Shrank(prev, next int) bool {
+	return float64(next) < minRefreshRatio*float64(prev)
+}
+
+// Loader fetches the full country dataset.
+type Loader func(ctx context.Context) ([]models.CountryDetails, error)
+
+// loadTimeout bounds the initial load, which no longer follows the deadline
+// of the caller that started it.
+const loadTimeout = time.Minute
+
+// Store holds the full country dataset in memory. It is loaded on first use
+// and shared by every caller; a failed load is retried on the next call.
+// Loads run without holding any lock readers need, and each new dataset is
+// published with an atomic swap.
+type Store struct {
+	current atomic.Pointer[version]
+	load    Loader
+
+	// mu guards pending, the initial load callers are waiting for.
+	mu      sync.Mutex
+	pending *initialLoad
+
+	// refreshMu serializes refreshes.
+	refreshMu sync.Mutex
+}
+
+// version is one published dataset.
+type version struct {
+	countries []models.CountryDetails
+	number    uint64
+}
+
+type initialLoad struct {
+	done    chan struct{}
+	current *version
+	err     error
+}
+
+func NewStore(load Loader) *Store {
+	return &Store{load: load}
+}
+
+// Countries returns the full dataset, loading it if it is not yet in memory.
+// The returned slice must not be modified.
+func (s *Store) Countries(ctx context.Context) ([]models.CountryDetails, error) {
+	countries, _, err := s.Snapshot(ctx)
+	return countries, err
+}
+
+// Peek returns the dataset and its version if it is already in memory,
+// without loading it.
+func (s *Store) Peek() ([]models.CountryDetails, uint64, bool) {
+	v := s.current.Load()
+	if v == nil {
+		return nil, 0, false
+	}
+	return v.countries, v.number, true
+}
+
+// Snapshot returns the full dataset together with its version, which changes
+// every time a new dataset is stored. Concurrent callers share one load,
+// which runs detached from the cancellation of whichever caller started it;
+// each caller stops waiting when its own ctx is done.
+func (s *Store) Snapshot(ctx context.Context) ([]models.CountryDetails, uint64, error) {
+	if v := s.current.Load(); v != nil {
+		return v.countries, v.number, nil
+	}
+
+	s.mu.Lock()
+	l := s.pending
+	if l == nil {
+		l = &initialLoad{done: make(chan struct{})}
+		s.pending = l
+		go s.loadInitial(context.WithoutCancel(ctx), l)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-l.done:
+		if l.err != nil {
+			return nil, 0, l.err
+		}
+		return l.current.countries, l.current.number, nil
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+}
+
+func (s *Store) loadInitial(ctx context.Context, l *initialLoad) {
+	ctx, cancel := context.WithTimeout(ctx, loadTimeout)
+	defer cancel()
+
+	countries, err := s.load(ctx)
+	if err == nil {
+		l.current = &version{countries: countries, number: 1}
+		// A refresh may have published a dataset while this one loaded.
+		if !s.current.CompareAndSwap(nil, l.current) {
+			l.current = s.current.Load()
+		}
+	}
+	l.err = err
+
+	s.mu.Lock()
+	s.pending = nil
+	s.mu.Unlock()
+	close(l.done)
+}
+
+// Refresh loads the dataset again and swaps it in, returning what changed
+// since the previous version. On failure, or when the new dataset holds less
+// than 90% of the current countries, the previous dataset stays in place.
+func (s *Store) Refresh(ctx context.Context) (Update, error) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	next, err := s.load(ctx)
+	if err != nil {
+		return Update{}, err
+	}
+	if len(next) == 0 {
+		return Update{}, ErrEmptyDataset
+	}
+
+	// Only an initial load finishing meanwhile can replace prev, since
+	// refreshes are serialized; compare against what it published then.
+	for {
+		prev := s.current.Load()
+		var countries []models.CountryDetails
+		var number uint64
+		if prev != nil {
+			countries, number = prev.countries, prev.number
+		}
+		if Shrank(len(countries), len(next)) {
+			return Update{}, fmt.Errorf("%w: %d countries, down from %d", ErrShrunkDataset, len(next), len(countries))
+		}
+
+		published := &version{countries: next, number: number + 1}
+		if s.current.CompareAndSwap(prev, published) {
+			return Update{
+				Version: published.number,
+				At:      time.Now().UTC(),
+				Changes: Diff(countries, next),
+			}, nil
+		}
+	}
+}
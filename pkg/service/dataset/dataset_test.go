@@ -0,0 +1,162 @@
+package dataset
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_LoadsOnce(t *testing.T) {
+	calls := 0
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		calls++
+		return []models.CountryDetails{{Name: models.CountryName{Common: "India"}}}, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			countries, err := store.Countries(context.Background())
+			assert.NoError(t, err)
+			assert.Len(t, countries, 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestStore_LoadIsDetachedFromCaller(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		calls.Add(1)
+		select {
+		case <-release:
+			return []models.CountryDetails{{Name: models.CountryName{Common: "India"}}}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := store.Countries(ctx)
+		first <- err
+	}()
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Readers are not blocked while the dataset loads.
+	_, _, ok := store.Peek()
+	assert.False(t, ok)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	second := make(chan error, 1)
+	go func() {
+		countries, err := store.Countries(context.Background())
+		assert.Len(t, countries, 1)
+		second <- err
+	}()
+	close(release)
+	assert.NoError(t, <-second)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestStore_RetriesAfterFailure(t *testing.T) {
+	fail := true
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return []models.CountryDetails{{Name: models.CountryName{Common: "India"}}}, nil
+	})
+
+	_, err := store.Countries(context.Background())
+	assert.Error(t, err)
+
+	fail = false
+	countries, err := store.Countries(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, countries, 1)
+}
+
+func TestStore_Refresh(t *testing.T) {
+	population := int64(1380004385)
+	var fail bool
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return []models.CountryDetails{{Name: models.CountryName{Common: "India"}, CCA3: "IND", Population: population}}, nil
+	})
+
+	_, version, err := store.Snapshot(context.Background())
+	assert.NoError(t, err)
+
+	population = 1417492000
+	update, err := store.Refresh(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, version+1, update.Version)
+	assert.Len(t, update.Changes, 1)
+	assert.Equal(t, "population", update.Changes[0].Fields[0].Field)
+
+	fail = true
+	_, err = store.Refresh(context.Background())
+	assert.Error(t, err)
+
+	countries, current, ok := store.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, update.Version, current)
+	assert.Equal(t, int64(1417492000), countries[0].Population)
+}
+
+func TestStore_RefreshRejectsEmptyDataset(t *testing.T) {
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		return []models.CountryDetails{}, nil
+	})
+
+	_, err := store.Refresh(context.Background())
+
+	assert.ErrorIs(t, err, ErrEmptyDataset)
+	_, _, ok := store.Peek()
+	assert.False(t, ok)
+}
+
+func TestStore_RefreshRejectsShrunkDataset(t *testing.T) {
+	countries := make([]models.CountryDetails, 10)
+	for i := range countries {
+		countries[i] = models.CountryDetails{CCA3: fmt.Sprintf("C%02d", i)}
+	}
+	loads := [][]models.CountryDetails{countries, countries[:8], countries[:9]}
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		next := loads[0]
+		loads = loads[1:]
+		return next, nil
+	})
+	ctx := context.Background()
+
+	_, err := store.Refresh(ctx)
+	assert.NoError(t, err)
+
+	_, err = store.Refresh(ctx)
+	assert.ErrorIs(t, err, ErrShrunkDataset)
+	current, version, _ := store.Peek()
+	assert.Len(t, current, 10)
+	assert.Equal(t, uint64(1), version)
+
+	update, err := store.Refresh(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, update.Changes, 1)
+}
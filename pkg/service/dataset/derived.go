@@ -0,0 +1,54 @@
+package dataset
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"sync"
+)
+
+// Derived caches a value computed from the dataset, such as a search index,
+// and rebuilds it whenever the dataset version changes.
+type Derived[T any] struct {
+	store *Store
+	build func([]models.CountryDetails) T
+
+	mu      sync.Mutex
+	version uint64
+	value   T
+}
+
+func NewDerived[T any](store *Store, build func([]models.CountryDetails) T) *Derived[T] {
+	return &Derived[T]{store: store, build: build}
+}
+
+// Get returns the value built from the current dataset.
+func (d *Derived[T]) Get(ctx context.Context) (T, error) {
+	countries, version, err := d.store.Snapshot(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return d.at(countries, version), nil
+}
+
+// Peek returns the value if the dataset is already in memory, without
+// loading it.
+func (d *Derived[T]) Peek() (T, bool) {
+	countries, version, ok := d.store.Peek()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return d.at(countries, version), true
+}
+
+func (d *Derived[T]) at(countries []models.CountryDetails, version uint64) T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.version != version {
+		d.value = d.build(countries)
+		d.version = version
+	}
+	return d.value
+}
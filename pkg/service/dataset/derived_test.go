@@ -0,0 +1,49 @@
+package dataset
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerived_BuildsOncePerVersion(t *testing.T) {
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		return []models.CountryDetails{{Name: models.CountryName{Common: "India"}}, {Name: models.CountryName{Common: "Japan"}}}, nil
+	})
+
+	builds := 0
+	count := NewDerived(store, func(countries []models.CountryDetails) int {
+		builds++
+		return len(countries)
+	})
+
+	for range 3 {
+		n, err := count.Get(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+	}
+	assert.Equal(t, 1, builds)
+}
+
+func TestDerived_PeekDoesNotLoad(t *testing.T) {
+	loads := 0
+	store := NewStore(func(ctx context.Context) ([]models.CountryDetails, error) {
+		loads++
+		return []models.CountryDetails{{Name: models.CountryName{Common: "India"}}}, nil
+	})
+	count := NewDerived(store, func(countries []models.CountryDetails) int { return len(countries) })
+
+	_, ok := count.Peek()
+	assert.False(t, ok)
+	assert.Equal(t, 0, loads)
+
+	_, err := store.Countries(context.Background())
+	assert.NoError(t, err)
+
+	n, ok := count.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, loads)
+}
@@ -0,0 +1,101 @@
+package dataset
+
+import (
+	"bytes"
+	"country-search-api/pkg/models"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Update describes one refresh of the dataset.
+type Update struct {
+	Version uint64    `json:"version"`
+	At      time.Time `json:"at"`
+	Changes []Change  `json:"changes"`
+}
+
+// Change describes how one country differs between two dataset versions.
+type Change struct {
+	CCA3 string `json:"cca3"`
+	Name string `json:"name"`
+	// Kind is "added", "removed" or "changed".
+	Kind string `json:"kind"`
+	// Fields lists the fields of a changed country that differ.
+	Fields []FieldChange `json:"fields,omitempty"`
+
+	// Previous and Current are the records on either side of the change;
+	// Previous is nil when the country was added and Current when removed.
+	Previous *models.CountryDetails `json:"-"`
+	Current  *models.CountryDetails `json:"-"`
+}
+
+// FieldChange holds the old and new JSON value of a field.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+// Diff compares two datasets country by country, keyed by alpha-3 code.
+// Changes are sorted by code. A nil prev is the initial load and yields no
+// changes.
+func Diff(prev, next []models.CountryDetails) []Change {
+	if prev == nil {
+		return []Change{}
+	}
+
+	before := byCCA3(prev)
+	after := byCCA3(next)
+
+	changes := []Change{}
+	for code, n := range after {
+		p, ok := before[code]
+		if !ok {
+			changes = append(changes, Change{CCA3: code, Name: n.Name.Common, Kind: "added", Current: &n})
+			continue
+		}
+		if fields := diffFields(p, n); len(fields) > 0 {
+			changes = append(changes, Change{CCA3: code, Name: n.Name.Common, Kind: "changed", Fields: fields, Previous: &p, Current: &n})
+		}
+	}
+	for code, p := range before {
+		if _, ok := after[code]; !ok {
+			changes = append(changes, Change{CCA3: code, Name: p.Name.Common, Kind: "removed", Previous: &p})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].CCA3 < changes[j].CCA3 })
+	return changes
+}
+
+func byCCA3(countries []models.CountryDetails) map[string]models.CountryDetails {
+	m := make(map[string]models.CountryDetails, len(countries))
+	for _, c := range countries {
+		if c.CCA3 != "" {
+			m[c.CCA3] = c
+		}
+	}
+	return m
+}
+
+// diffFields compares the JSON encoding of each detail field.
+func diffFields(prev, next models.CountryDetails) []FieldChange {
+	fields := models.DetailFields()
+	before, err := prev.Project(fields)
+	if err != nil {
+		return nil
+	}
+	after, err := next.Project(fields)
+	if err != nil {
+		return nil
+	}
+
+	var changes []FieldChange
+	for _, f := range fields {
+		if !bytes.Equal(before[f], after[f]) {
+			changes = append(changes, FieldChange{Field: f, Old: before[f], New: after[f]})
+		}
+	}
+	return changes
+}
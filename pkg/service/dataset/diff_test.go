@@ -0,0 +1,50 @@
+package dataset
+
+import (
+	"country-search-api/pkg/models"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	prev := []models.CountryDetails{
+		{Name: models.CountryName{Common: "Sierra Leone"}, CCA3: "SLE", Capitals: []string{"Freetown"}, Currencies: []models.Currency{{Code: "SLL", Symbol: "Le"}}},
+		{Name: models.CountryName{Common: "Swaziland"}, CCA3: "SWZ", Population: 1160164},
+		{Name: models.CountryName{Common: "Yugoslavia"}, CCA3: "YUG"},
+	}
+	next := []models.CountryDetails{
+		{Name: models.CountryName{Common: "Sierra Leone"}, CCA3: "SLE", Capitals: []string{"Freetown"}, Currencies: []models.Currency{{Code: "SLE", Symbol: "Le"}}},
+		{Name: models.CountryName{Common: "Eswatini"}, CCA3: "SWZ", Population: 1160164},
+		{Name: models.CountryName{Common: "Serbia"}, CCA3: "SRB"},
+	}
+
+	changes := Diff(prev, next)
+
+	assert.Len(t, changes, 4)
+	assert.Equal(t, "SLE", changes[0].CCA3)
+	assert.Equal(t, "changed", changes[0].Kind)
+	assert.Equal(t, []FieldChange{{
+		Field: "currencies",
+		Old:   json.RawMessage(`[{"code":"SLL","name":"","symbol":"Le","minorUnits":0}]`),
+		New:   json.RawMessage(`[{"code":"SLE","name":"","symbol":"Le","minorUnits":0}]`),
+	}}, changes[0].Fields)
+	assert.Equal(t, prev[0], *changes[0].Previous)
+	assert.Equal(t, next[0], *changes[0].Current)
+	assert.Equal(t, "SRB", changes[1].CCA3)
+	assert.Equal(t, "added", changes[1].Kind)
+	assert.Nil(t, changes[1].Previous)
+	assert.Equal(t, "SWZ", changes[2].CCA3)
+	assert.Equal(t, "name", changes[2].Fields[0].Field)
+	assert.Equal(t, "YUG", changes[3].CCA3)
+	assert.Equal(t, "removed", changes[3].Kind)
+	assert.Nil(t, changes[3].Current)
+}
+
+func TestDiff_InitialLoad(t *testing.T) {
+	next := []models.CountryDetails{{Name: models.CountryName{Common: "India"}, CCA3: "IND"}}
+
+	assert.Empty(t, Diff(nil, next))
+	assert.Empty(t, Diff(next, next))
+}
@@ -0,0 +1,42 @@
+package dataset
+
+import "sync"
+
+// History keeps the most recent dataset updates, dropping the oldest once
+// it holds size entries.
+type History struct {
+	mu      sync.Mutex
+	size    int
+	updates []Update
+}
+
+func NewHistory(size int) *History {
+	return &History{size: size}
+}
+
+// Add records update, evicting the oldest entry when the history is full.
+func (h *History) Add(update Update) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.updates = append(h.updates, update)
+	if len(h.updates) > h.size {
+		h.updates = h.updates[len(h.updates)-h.size:]
+	}
+}
+
+// List returns up to limit updates, newest first. A limit of zero or less
+// returns them all.
+func (h *History) List(limit int) []Update {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if limit <= 0 || limit > len(h.updates) {
+		limit = len(h.updates)
+	}
+	updates := make([]Update, 0, limit)
+	for i := len(h.updates) - 1; i >= 0 && len(updates) < limit; i-- {
+		updates = append(updates, h.updates[i])
+	}
+	return updates
+}
@@ -0,0 +1,24 @@
+package dataset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory_BoundedNewestFirst(t *testing.T) {
+	h := NewHistory(3)
+	for v := range uint64(5) {
+		h.Add(Update{Version: v + 1})
+	}
+
+	versions := []uint64{}
+	for _, u := range h.List(0) {
+		versions = append(versions, u.Version)
+	}
+	assert.Equal(t, []uint64{5, 4, 3}, versions)
+
+	assert.Len(t, h.List(2), 2)
+	assert.Equal(t, uint64(5), h.List(1)[0].Version)
+	assert.Empty(t, NewHistory(3).List(10))
+}
@@ -0,0 +1,103 @@
+// Package filewatch keeps a value parsed from a file up to date, reloading
+// it whenever the file changes on disk.
+package filewatch
+
+import (
+	"context"
+	"country-search-api/pkg/logger"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// File holds the value parsed from a file and reloads it by polling the
+// file's size and modification time. It is safe for concurrent use.
+type File[T any] struct {
+	path  string
+	name  string
+	parse func(data []byte) (T, error)
+	value atomic.Pointer[T]
+
+	// mu serializes reloads and guards the version of the file loaded.
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// Open parses the file at path. name describes its contents in log
+// messages, such as "exchange rates".
+func Open[T any](path, name string, parse func(data []byte) (T, error)) (*File[T], error) {
+	f := &File[T]{path: path, name: name, parse: parse}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File[T]) load() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	value, err := f.parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+
+	f.value.Store(&value)
+	f.modTime, f.size = info.ModTime(), info.Size()
+	return nil
+}
+
+// Value returns the value parsed from the file when it was last loaded.
+func (f *File[T]) Value() T {
+	return *f.value.Load()
+}
+
+// Watch checks the file every interval until ctx is done and reloads it
+// when its size or modification time changes. A file that fails to load
+// is logged and the previous value kept.
+func (f *File[T]) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := f.Reload(); err != nil {
+				logger.Log().Error("unable to reload "+f.name+", keeping previous version:", "file", f.path, "error", err)
+			}
+		}
+	}
+}
+
+// Reload loads the file again if it changed since it was last loaded and
+// reports whether it did.
+func (f *File[T]) Reload() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+	if err := f.load(); err != nil {
+		// Remember the broken version so it is not retried every tick.
+		f.modTime, f.size = info.ModTime(), info.Size()
+		return false, err
+	}
+	logger.Log().Info("reloaded "+f.name+":", "file", f.path)
+	return true, nil
+}
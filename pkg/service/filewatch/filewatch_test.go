@@ -0,0 +1,61 @@
+package filewatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseInt(data []byte) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func TestFile_ReloadsWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "value.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("1\n"), 0o600))
+
+	f, err := Open(path, "value", parseInt)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.Value())
+
+	reloaded, err := f.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	assert.NoError(t, os.WriteFile(path, []byte("2\n"), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	reloaded, err = f.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, 2, f.Value())
+
+	// A broken file keeps the previous value and is not retried until it
+	// changes again.
+	assert.NoError(t, os.WriteFile(path, []byte("two\n"), 0o600))
+	_, err = f.Reload()
+	assert.ErrorContains(t, err, path)
+	assert.Equal(t, 2, f.Value())
+	reloaded, err = f.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.Watch(ctx, time.Millisecond) // returns once ctx is done
+}
+
+func TestOpen_Errors(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing.txt"), "value", parseInt)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "value.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("one"), 0o600))
+	_, err = Open(path, "value", parseInt)
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
@@ -0,0 +1,45 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNoFlag = errors.New("flag not found")
+
+// regionalIndicatorA is REGIONAL INDICATOR SYMBOL LETTER A. A pair of
+// regional indicators spelling an ISO 3166-1 alpha-2 code is rendered as
+// that country's flag.
+const regionalIndicatorA = 0x1F1E6
+
+// Emoji returns the flag emoji of the country with ISO 3166-1 alpha-2 code
+// cca2, such as 🇩🇪 for "DE".
+func Emoji(cca2 string) (string, error) {
+	if !isAlpha2(cca2) {
+		return "", fmt.Errorf("%w: %q is not an ISO 3166-1 alpha-2 code", ErrNoFlag, cca2)
+	}
+	return string([]rune{
+		regionalIndicatorA + rune(upper(cca2[0])-'A'),
+		regionalIndicatorA + rune(upper(cca2[1])-'A'),
+	}), nil
+}
+
+// isAlpha2 reports whether code is two ASCII letters.
+func isAlpha2(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for i := range 2 {
+		if c := upper(code[i]); c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
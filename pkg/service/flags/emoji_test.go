@@ -0,0 +1,22 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmoji(t *testing.T) {
+	emoji, err := Emoji("DE")
+	assert.NoError(t, err)
+	assert.Equal(t, "🇩🇪", emoji)
+
+	emoji, err = Emoji("jp")
+	assert.NoError(t, err)
+	assert.Equal(t, "🇯🇵", emoji)
+
+	for _, code := range []string{"", "D", "DEU", "D1", "É"} {
+		_, err = Emoji(code)
+		assert.ErrorIs(t, err, ErrNoFlag, code)
+	}
+}
@@ -0,0 +1,127 @@
+package flags
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format is a representation of a flag.
+type Format string
+
+const (
+	// EmojiFormat is the flag emoji, served as JSON.
+	EmojiFormat Format = "emoji"
+	SVG         Format = "svg"
+	PNG         Format = "png"
+)
+
+// formats lists every Format in order of preference when a client accepts
+// several equally.
+var formats = []Format{EmojiFormat, SVG, PNG}
+
+var (
+	ErrInvalidFormat = errors.New("invalid flag format")
+	ErrNotAcceptable = errors.New("no acceptable flag format")
+)
+
+// ParseFormat parses "emoji", "svg" or "png".
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q, want emoji, svg or png", ErrInvalidFormat, s)
+}
+
+// ContentType returns the media type f is served as.
+func (f Format) ContentType() string {
+	switch f {
+	case SVG:
+		return "image/svg+xml"
+	case PNG:
+		return "image/png"
+	default:
+		return "application/json"
+	}
+}
+
+// Negotiate picks the format an Accept header prefers. Each format takes
+// the quality of the most specific media range matching it, so
+// "image/*, image/png;q=0" rules PNG out; ties go to the emoji, then SVG.
+// An empty header accepts anything.
+func Negotiate(accept string) (Format, error) {
+	if strings.TrimSpace(accept) == "" {
+		return formats[0], nil
+	}
+	ranges := parseAccept(accept)
+
+	var best Format
+	bestQ := 0.0
+	for _, f := range formats {
+		if q := quality(ranges, f.ContentType()); q > bestQ {
+			best, bestQ = f, q
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("%w: accepted %s, want application/json, image/svg+xml or image/png", ErrNotAcceptable, accept)
+	}
+	return best, nil
+}
+
+// mediaRange is one entry of an Accept header.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept reads the media ranges of an Accept header, skipping
+// malformed ones. A missing or malformed q counts as 1.
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, _ := strings.Cut(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mt)), "/")
+		if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+			continue
+		}
+
+		r := mediaRange{typ: typ, subtype: subtype, q: 1}
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil && q >= 0 && q <= 1 {
+					r.q = q
+				}
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// quality returns the q of the most specific range matching contentType,
+// or 0 if none does.
+func quality(ranges []mediaRange, contentType string) float64 {
+	typ, subtype, _ := strings.Cut(contentType, "/")
+
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		s := -1
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
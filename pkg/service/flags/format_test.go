@@ -0,0 +1,47 @@
+package flags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat(" SVG ")
+	assert.NoError(t, err)
+	assert.Equal(t, SVG, f)
+
+	_, err = ParseFormat("gif")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   Format
+	}{
+		{"", EmojiFormat},
+		{"*/*", EmojiFormat},
+		{"application/json", EmojiFormat},
+		{"image/png", PNG},
+		{"image/*", SVG},
+		{"image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8", SVG},
+		{"image/png, image/svg+xml;q=0.9", PNG},
+		{"image/svg+xml;q=0.5, image/png", PNG},
+		{"image/*, image/svg+xml;q=0", PNG},
+		{"IMAGE/PNG;Q=0.7, text/html", PNG},
+		{"image/png;q=abc, image/svg+xml;q=0.9", PNG},
+	}
+	for _, tt := range tests {
+		got, err := Negotiate(tt.accept)
+		assert.NoError(t, err, tt.accept)
+		assert.Equal(t, tt.want, got, tt.accept)
+	}
+}
+
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	for _, accept := range []string{"text/html", "image/gif", "*/*;q=0", "image/*;q=0, application/json;q=0"} {
+		_, err := Negotiate(accept)
+		assert.ErrorIs(t, err, ErrNotAcceptable, accept)
+	}
+}
@@ -0,0 +1,109 @@
+package flags
+
+import (
+	"bytes"
+	"context"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/cache"
+	http_client "country-search-api/pkg/service/client"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNoImages = errors.New("flag images are not configured")
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Image is a flag in SVG or PNG.
+type Image struct {
+	Format Format
+	Data   []byte
+}
+
+// Store supplies flag images.
+type Store interface {
+	Image(ctx context.Context, country models.CountryDetails, format Format) (Image, error)
+}
+
+// Dir is a Store reading flags from a local directory of files named after
+// the lower-case alpha-2 code, such as de.svg and de.png, the layout of the
+// flagcdn.com downloads.
+type Dir string
+
+func (d Dir) Image(_ context.Context, country models.CountryDetails, format Format) (Image, error) {
+	if !isAlpha2(country.CCA2) {
+		return Image{}, fmt.Errorf("%w: %s has no alpha-2 code", ErrNoFlag, country.Name.Common)
+	}
+
+	path := filepath.Join(string(d), strings.ToLower(country.CCA2)+"."+string(format))
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Image{}, fmt.Errorf("%w: no %s image for %s", ErrNoFlag, format, country.Name.Common)
+	}
+	if err != nil {
+		return Image{}, err
+	}
+	return Image{Format: format, Data: data}, nil
+}
+
+// Remote is a Store fetching flags from the image URLs of the country
+// record and caching them in memory. It is safe for concurrent use.
+type Remote struct {
+	client http_client.ClientInf
+	cache  cache.CacheInf
+}
+
+// NewRemote returns a Remote fetching images with client.
+func NewRemote(client http_client.ClientInf) *Remote {
+	return &Remote{client: client, cache: cache.NewCache()}
+}
+
+func (r *Remote) Image(ctx context.Context, country models.CountryDetails, format Format) (Image, error) {
+	url := country.Flag.SVG
+	if format == PNG {
+		url = country.Flag.PNG
+	}
+	if url == "" {
+		return Image{}, fmt.Errorf("%w: no %s image for %s", ErrNoFlag, format, country.Name.Common)
+	}
+
+	if data, ok := r.cache.Get(url); ok {
+		return Image{Format: format, Data: data.([]byte)}, nil
+	}
+
+	data, err := r.client.Get(ctx, url)
+	if errors.Is(err, http_client.ErrNotFound) {
+		return Image{}, fmt.Errorf("%w: no %s image for %s", ErrNoFlag, format, country.Name.Common)
+	}
+	if err != nil {
+		return Image{}, err
+	}
+	if !looksLike(format, data) {
+		return Image{}, fmt.Errorf("%w: %s is not a %s image", http_client.ErrUpstream, url, format)
+	}
+
+	r.cache.Set(url, data)
+	return Image{Format: format, Data: data}, nil
+}
+
+// looksLike reports whether data plausibly is an image in format, so that
+// an error page is never cached as a flag.
+func looksLike(format Format, data []byte) bool {
+	if format == PNG {
+		return bytes.HasPrefix(data, pngSignature)
+	}
+	return bytes.Contains(data, []byte("<svg"))
+}
+
+// ETag returns a strong entity tag for data.
+func ETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,76 @@
+package flags
+
+import (
+	"context"
+	mock_http_client "country-search-api/mock/ClientInf"
+	"country-search-api/pkg/models"
+	http_client "country-search-api/pkg/service/client"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	svgFlag = []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 5 3"><path d="M0 0h5v3H0z"/></svg>`)
+	pngFlag = append([]byte("\x89PNG\r\n\x1a\n"), "IHDR"...)
+)
+
+var germany = models.CountryDetails{
+	Name: models.CountryName{Common: "Germany"},
+	CCA2: "DE",
+	Flag: models.Flag{SVG: "https://flagcdn.com/de.svg", PNG: "https://flagcdn.com/w320/de.png"},
+}
+
+func TestDir_Image(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "de.svg"), svgFlag, 0o600))
+
+	img, err := Dir(dir).Image(context.Background(), germany, SVG)
+	assert.NoError(t, err)
+	assert.Equal(t, Image{Format: SVG, Data: svgFlag}, img)
+
+	_, err = Dir(dir).Image(context.Background(), germany, PNG)
+	assert.ErrorIs(t, err, ErrNoFlag)
+
+	// A record without a usable alpha-2 code never turns into a path.
+	_, err = Dir(dir).Image(context.Background(), models.CountryDetails{CCA2: "../de"}, SVG)
+	assert.ErrorIs(t, err, ErrNoFlag)
+}
+
+func TestRemote_ImageIsCached(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", context.Background(), "https://flagcdn.com/w320/de.png").Return(pngFlag, nil).Once()
+	r := NewRemote(mockClient)
+
+	for range 2 {
+		img, err := r.Image(context.Background(), germany, PNG)
+		assert.NoError(t, err)
+		assert.Equal(t, Image{Format: PNG, Data: pngFlag}, img)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+func TestRemote_ImageErrors(t *testing.T) {
+	mockClient := new(mock_http_client.MockClientInf)
+	mockClient.On("Get", context.Background(), "https://flagcdn.com/de.svg").Return([]byte("<html>maintenance</html>"), nil).Once()
+	mockClient.On("Get", context.Background(), "https://flagcdn.com/w320/de.png").Return(nil, http_client.ErrNotFound).Once()
+	r := NewRemote(mockClient)
+
+	_, err := r.Image(context.Background(), germany, SVG)
+	assert.ErrorIs(t, err, http_client.ErrUpstream)
+
+	_, err = r.Image(context.Background(), germany, PNG)
+	assert.ErrorIs(t, err, ErrNoFlag)
+
+	_, err = r.Image(context.Background(), models.CountryDetails{CCA2: "AQ"}, SVG)
+	assert.ErrorIs(t, err, ErrNoFlag)
+	mockClient.AssertExpectations(t)
+}
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, ETag(svgFlag), ETag(append([]byte(nil), svgFlag...)))
+	assert.NotEqual(t, ETag(svgFlag), ETag(pngFlag))
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, ETag(svgFlag))
+}
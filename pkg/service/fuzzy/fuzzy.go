@@ -0,0 +1,214 @@
+package fuzzy
+
+import (
+	"country-search-api/pkg/models"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MinScore is the lowest similarity reported as a suggestion.
+	MinScore = 0.6
+	// ConfidentScore is the similarity above which a lone best match is
+	// trusted to stand in for the query.
+	ConfidentScore = 0.8
+	// confidentMargin is how far ahead of the runner-up a confident match
+	// must be.
+	confidentMargin = 0.1
+)
+
+// Suggestion is a country whose names resemble the query.
+type Suggestion struct {
+	Name    string  `json:"name"`
+	CCA3    string  `json:"cca3"`
+	Matched string  `json:"matched"`
+	Score   float64 `json:"score"`
+}
+
+type term struct {
+	normalized string
+	original   string
+	country    int
+}
+
+// Index matches queries against every known name of a set of countries:
+// common and official names, native names, alternate spellings and
+// translations.
+type Index struct {
+	countries []models.CountryDetails
+	terms     []term
+}
+
+func NewIndex(countries []models.CountryDetails) *Index {
+	idx := &Index{countries: countries}
+	for i, c := range countries {
+		seen := make(map[string]bool)
+		add := func(name string) {
+			n := Normalize(name)
+			if n == "" || seen[n] {
+				return
+			}
+			seen[n] = true
+			idx.terms = append(idx.terms, term{normalized: n, original: name, country: i})
+		}
+
+		add(c.Name.Common)
+		add(c.Name.Official)
+		for _, native := range c.Name.Native {
+			add(native.Common)
+			add(native.Official)
+		}
+		for _, alt := range c.AltSpellings {
+			add(alt)
+		}
+		for _, t := range c.Translations {
+			add(t.Common)
+			add(t.Official)
+		}
+	}
+	return idx
+}
+
+// Suggest returns up to limit countries scoring at least MinScore against
+// query, best first, with one entry per country.
+func (idx *Index) Suggest(query string, limit int) []Suggestion {
+	q := Normalize(query)
+	if q == "" || limit <= 0 {
+		return nil
+	}
+
+	best := make(map[int]Suggestion)
+	for _, t := range idx.terms {
+		score := Similarity(q, t.normalized)
+		if score < MinScore {
+			continue
+		}
+		if s, ok := best[t.country]; ok && s.Score >= score {
+			continue
+		}
+		c := idx.countries[t.country]
+		best[t.country] = Suggestion{Name: c.Name.Common, CCA3: c.CCA3, Matched: t.original, Score: score}
+	}
+
+	suggestions := make([]Suggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// Resolve returns the country for query when a single suggestion is
+// confident enough to stand in for it.
+func (idx *Index) Resolve(query string) (models.CountryDetails, Suggestion, bool) {
+	suggestions := idx.Suggest(query, 2)
+	if len(suggestions) == 0 || suggestions[0].Score < ConfidentScore {
+		return models.CountryDetails{}, Suggestion{}, false
+	}
+	if len(suggestions) > 1 && suggestions[0].Score-suggestions[1].Score < confidentMargin {
+		return models.CountryDetails{}, Suggestion{}, false
+	}
+
+	for _, c := range idx.countries {
+		if c.CCA3 == suggestions[0].CCA3 {
+			return c, suggestions[0], true
+		}
+	}
+	return models.CountryDetails{}, Suggestion{}, false
+}
+
+// Normalize lower-cases s, strips diacritics and collapses whitespace.
+func Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// ContainsWords reports whether words occurs in s on word boundaries. Both
+// are expected to be normalized.
+func ContainsWords(s, words string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], words)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(words)
+		if (start == 0 || !isWordByte(s[start-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// Words splits a normalized string into the words ContainsWords matches on.
+func Words(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r < 0x80 && !isWordByte(byte(r)) })
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+// Similarity scores a and b between 0 and 1 from their Damerau-Levenshtein
+// distance relative to the longer string.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(distance(ra, rb))/float64(longest)
+}
+
+// distance is the optimal string alignment variant of the Damerau-Levenshtein
+// distance: insertions, deletions, substitutions and adjacent transpositions.
+func distance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
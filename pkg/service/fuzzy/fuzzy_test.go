@@ -0,0 +1,66 @@
+package fuzzy
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var countries = []models.CountryDetails{
+	{Name: models.CountryName{Common: "Germany", Official: "Federal Republic of Germany"}, CCA3: "DEU", AltSpellings: []string{"DE", "Bundesrepublik Deutschland"},
+		Translations: map[string]models.NativeName{"fra": {Common: "Allemagne"}}},
+	{Name: models.CountryName{Common: "Guernsey", Official: "Bailiwick of Guernsey"}, CCA3: "GGY"},
+	{Name: models.CountryName{Common: "Côte d'Ivoire", Official: "Republic of Côte d'Ivoire"}, CCA3: "CIV", AltSpellings: []string{"Ivory Coast"}},
+	{Name: models.CountryName{Common: "Austria", Official: "Republic of Austria"}, CCA3: "AUT"},
+	{Name: models.CountryName{Common: "Australia", Official: "Commonwealth of Australia"}, CCA3: "AUS"},
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"germany", "germny", 1},
+		{"germany", "gemrany", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"same", "same", 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, distance([]rune(tt.a), []rune(tt.b)), tt.a+"/"+tt.b)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "cote d'ivoire", Normalize("  Côte   d'Ivoire "))
+}
+
+func TestSuggest(t *testing.T) {
+	idx := NewIndex(countries)
+
+	suggestions := idx.Suggest("Germny", 3)
+
+	assert.NotEmpty(t, suggestions)
+	assert.Equal(t, "Germany", suggestions[0].Name)
+	assert.Equal(t, "DEU", suggestions[0].CCA3)
+
+	suggestions = idx.Suggest("Allemange", 3)
+	assert.Equal(t, "Allemagne", suggestions[0].Matched)
+
+	assert.Empty(t, idx.Suggest("Atlantis", 3))
+}
+
+func TestResolve(t *testing.T) {
+	idx := NewIndex(countries)
+
+	country, suggestion, ok := idx.Resolve("cote divoire")
+	assert.True(t, ok)
+	assert.Equal(t, "CIV", country.CCA3)
+	assert.Equal(t, "Côte d'Ivoire", suggestion.Matched)
+
+	// Close to both Austria and Australia, so it must not be auto-resolved.
+	_, _, ok = idx.Resolve("Austrlia")
+	assert.False(t, ok)
+}
@@ -0,0 +1,216 @@
+package geo
+
+import (
+	"country-search-api/pkg/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+)
+
+var (
+	ErrInvalidBoundaries = errors.New("invalid country boundaries")
+	ErrNoBoundaries      = errors.New("country boundaries not loaded")
+)
+
+// codeProperties are the feature properties checked, in order, for the ISO
+// 3166-1 code of a boundary. They cover REST Countries style data, Natural
+// Earth and the common datasets derived from it.
+var codeProperties = []string{
+	"cca3", "ISO_A3", "ISO_A3_EH", "ADM0_A3", "iso_a3", "ISO3166-1-Alpha-3",
+	"cca2", "ISO_A2", "iso_a2", "ISO3166-1-Alpha-2",
+}
+
+// isoCode filters out placeholders such as Natural Earth's "-99".
+var isoCode = regexp.MustCompile(`^[A-Z]{2,3}$`)
+
+// Boundaries answers which country contains a point from country boundary
+// polygons. It is immutable once built and safe for concurrent use.
+type Boundaries struct {
+	polygons []polygon
+	index    *rtree
+}
+
+// polygon is one part of a country's boundary: an outer ring and the holes
+// cut out of it, each a closed list of [lng, lat] positions whose
+// longitudes are unwrapped so the ring never jumps across the antimeridian.
+type polygon struct {
+	code  string
+	rings [][][2]float64
+	box   Box
+}
+
+// LoadBoundaries reads a GeoJSON FeatureCollection of country boundaries.
+func LoadBoundaries(path string) (*Boundaries, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBoundaries(data)
+}
+
+// ParseBoundaries parses a GeoJSON FeatureCollection whose features carry
+// a Polygon or MultiPolygon geometry and an ISO 3166-1 code property.
+// Features without a usable code or geometry are skipped.
+func ParseBoundaries(data []byte) (*Boundaries, error) {
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			ID         any            `json:"id"`
+			Properties map[string]any `json:"properties"`
+			Geometry   *struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoundaries, err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("%w: expected a FeatureCollection, got %q", ErrInvalidBoundaries, fc.Type)
+	}
+
+	b := &Boundaries{}
+	for i, f := range fc.Features {
+		code := featureCode(f.Properties, f.ID)
+		if code == "" || f.Geometry == nil {
+			continue
+		}
+
+		var parts [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var rings [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &rings); err != nil {
+				return nil, fmt.Errorf("%w: feature %d: %w", ErrInvalidBoundaries, i, err)
+			}
+			parts = [][][][2]float64{rings}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &parts); err != nil {
+				return nil, fmt.Errorf("%w: feature %d: %w", ErrInvalidBoundaries, i, err)
+			}
+		default:
+			continue
+		}
+
+		for _, rings := range parts {
+			if len(rings) == 0 || slices.ContainsFunc(rings, func(r [][2]float64) bool { return len(r) < 4 }) {
+				continue
+			}
+			p := polygon{code: code, box: emptyBox()}
+			for _, ring := range rings {
+				p.rings = append(p.rings, unwrap(ring))
+			}
+			for _, pos := range p.rings[0] {
+				p.box = p.box.extend(Box{MinLng: pos[0], MinLat: pos[1], MaxLng: pos[0], MaxLat: pos[1]})
+			}
+			// Holes are unwrapped on their own, so move them to the turn
+			// of the outer ring they are cut from.
+			mid := center(p.box).lng
+			for _, hole := range p.rings[1:] {
+				shift := 360 * math.Round((mid-hole[0][0])/360)
+				for k := range hole {
+					hole[k][0] += shift
+				}
+			}
+			b.polygons = append(b.polygons, p)
+		}
+	}
+	if len(b.polygons) == 0 {
+		return nil, fmt.Errorf("%w: no country polygons", ErrInvalidBoundaries)
+	}
+
+	boxes := make([]Box, len(b.polygons))
+	for i, p := range b.polygons {
+		boxes[i] = p.box
+	}
+	b.index = newRTree(boxes)
+	return b, nil
+}
+
+func featureCode(properties map[string]any, id any) string {
+	for _, key := range codeProperties {
+		if code, ok := properties[key].(string); ok && isoCode.MatchString(code) {
+			return code
+		}
+	}
+	if code, ok := id.(string); ok && isoCode.MatchString(code) {
+		return code
+	}
+	return ""
+}
+
+// unwrap shifts longitudes by whole turns wherever consecutive positions
+// are more than 180° apart, so a ring drawn across the antimeridian, such
+// as Fiji's or Chukotka's, stays one contiguous shape.
+func unwrap(ring [][2]float64) [][2]float64 {
+	out := make([][2]float64, len(ring))
+	var shift float64
+	for i, pos := range ring {
+		if i > 0 {
+			prev := ring[i-1][0]
+			switch {
+			case pos[0]-prev > 180:
+				shift -= 360
+			case prev-pos[0] > 180:
+				shift += 360
+			}
+		}
+		out[i] = [2]float64{pos[0] + shift, pos[1]}
+	}
+	return out
+}
+
+// Locate returns the code of the country whose boundary contains p.
+func (b *Boundaries) Locate(p models.LatLng) (string, bool) {
+	// Unwrapped polygons may extend past ±180°, so also try the point one
+	// turn east and west.
+	for _, lng := range []float64{p.Lng, p.Lng + 360, p.Lng - 360} {
+		code := ""
+		b.index.search(lng, p.Lat, func(entry int) {
+			if code == "" && b.polygons[entry].contains(lng, p.Lat) {
+				code = b.polygons[entry].code
+			}
+		})
+		if code != "" {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// Len returns how many polygons are indexed.
+func (b *Boundaries) Len() int {
+	return len(b.polygons)
+}
+
+// contains reports whether the point lies inside the outer ring and
+// outside every hole.
+func (p polygon) contains(lng, lat float64) bool {
+	if !inRing(p.rings[0], lng, lat) {
+		return false
+	}
+	for _, hole := range p.rings[1:] {
+		if inRing(hole, lng, lat) {
+			return false
+		}
+	}
+	return true
+}
+
+// inRing is the even-odd ray casting test.
+func inRing(ring [][2]float64, lng, lat float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,108 @@
+package geo
+
+import (
+	"country-search-api/pkg/models"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// boundaries are squared-off stand-ins for real borders: South Africa with
+// Lesotho cut out of it, and Fiji as a MultiPolygon whose main part crosses
+// the antimeridian.
+const boundaries = `{
+	"type": "FeatureCollection",
+	"features": [
+		{"type": "Feature", "properties": {"ISO_A3": "ZAF"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[16, -35], [33, -35], [33, -22], [16, -22], [16, -35]],
+			[[27, -31], [29.5, -31], [29.5, -28.5], [27, -28.5], [27, -31]]
+		]}},
+		{"type": "Feature", "properties": {"ISO_A3": "-99", "ADM0_A3": "LSO"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[27, -31], [29.5, -31], [29.5, -28.5], [27, -28.5], [27, -31]]
+		]}},
+		{"type": "Feature", "id": "FJI", "properties": {}, "geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[177, -19], [-179, -19], [-179, -16], [177, -16], [177, -19]]],
+			[[[-179, -20], [-178, -20], [-178, -19.5], [-179, -19.5], [-179, -20]]]
+		]}},
+		{"type": "Feature", "properties": {"name": "Nowhere"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]
+		]}}
+	]
+}`
+
+func locate(b *Boundaries, lat, lng float64) string {
+	code, _ := b.Locate(models.LatLng{Lat: lat, Lng: lng})
+	return code
+}
+
+func TestBoundaries_Locate(t *testing.T) {
+	b, err := ParseBoundaries([]byte(boundaries))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, b.Len())
+
+	assert.Equal(t, "ZAF", locate(b, -26.2, 28.05))  // Johannesburg
+	assert.Equal(t, "LSO", locate(b, -29.31, 27.48)) // Maseru, inside the hole
+	assert.Equal(t, "FJI", locate(b, -18.14, 178.44))
+	assert.Equal(t, "FJI", locate(b, -17, -179.5))   // east of the antimeridian
+	assert.Equal(t, "FJI", locate(b, -19.8, -178.5)) // second part
+	assert.Empty(t, locate(b, -17, 176))
+	assert.Empty(t, locate(b, 0.5, 0.5)) // feature without a code
+	assert.Empty(t, locate(b, -40, 20))  // ocean
+}
+
+func TestBoundaries_HoleAcrossAntimeridian(t *testing.T) {
+	b, err := ParseBoundaries([]byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"cca3": "AAA"}, "geometry": {"type": "Polygon", "coordinates": [
+			[[170, -10], [-170, -10], [-170, 10], [170, 10], [170, -10]],
+			[[-179, -1], [-179, 1], [179, 1], [179, -1], [-179, -1]]
+		]}}
+	]}`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "AAA", locate(b, 5, 175))
+	assert.Equal(t, "AAA", locate(b, 5, -175))
+	assert.Empty(t, locate(b, 0, 180))
+	assert.Empty(t, locate(b, 0, -179.5))
+}
+
+func TestLoadBoundaries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "countries.geojson")
+	assert.NoError(t, os.WriteFile(path, []byte(boundaries), 0o600))
+
+	b, err := LoadBoundaries(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "ZAF", locate(b, -33.9, 18.4))
+
+	for _, data := range []string{`[]`, `{"type": "Feature"}`, `{"type": "FeatureCollection", "features": []}`} {
+		_, err := ParseBoundaries([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidBoundaries, data)
+	}
+}
+
+func TestRTree_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	boxes := make([]Box, 500)
+	for i := range boxes {
+		lng, lat := rng.Float64()*360-180, rng.Float64()*180-90
+		boxes[i] = Box{MinLng: lng, MinLat: lat, MaxLng: lng + rng.Float64()*20, MaxLat: lat + rng.Float64()*10}
+	}
+	tree := newRTree(boxes)
+
+	for i := 0; i < 200; i++ {
+		lng, lat := rng.Float64()*360-180, rng.Float64()*180-90
+		var want, got []int
+		for j, b := range boxes {
+			if b.contains(lng, lat) {
+				want = append(want, j)
+			}
+		}
+		tree.search(lng, lat, func(entry int) { got = append(got, entry) })
+		slices.Sort(got)
+		assert.Equal(t, want, got, fmt.Sprintf("%f,%f", lat, lng))
+	}
+}
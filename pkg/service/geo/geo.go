@@ -0,0 +1,100 @@
+package geo
+
+import (
+	"country-search-api/pkg/models"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EarthRadiusKm is the mean radius of the Earth used for great-circle
+// distances.
+const EarthRadiusKm = 6371.0088
+
+var (
+	ErrInvalidPoint  = errors.New("invalid coordinates")
+	ErrInvalidAnchor = errors.New("invalid anchor")
+	ErrNoCoordinates = errors.New("country has no coordinates")
+)
+
+// MaxNearest bounds how many countries a nearest-neighbor query returns.
+const MaxNearest = 25
+
+// Anchor chooses which coordinates stand for a country.
+type Anchor string
+
+const (
+	// AnchorCountry uses the country's reference point.
+	AnchorCountry Anchor = "country"
+	// AnchorCapital uses the location of the country's first capital.
+	AnchorCapital Anchor = "capital"
+)
+
+// ParseAnchor parses the by= query parameter; empty means AnchorCountry.
+func ParseAnchor(s string) (Anchor, error) {
+	switch Anchor(s) {
+	case "", AnchorCountry:
+		return AnchorCountry, nil
+	case AnchorCapital:
+		return AnchorCapital, nil
+	default:
+		return "", fmt.Errorf("%w: %q, must be country or capital", ErrInvalidAnchor, s)
+	}
+}
+
+// Of returns the coordinates of c for the anchor, or nil when they are
+// unknown.
+func (a Anchor) Of(c models.CountryDetails) *models.LatLng {
+	if a == AnchorCapital {
+		return c.CapitalLatLng
+	}
+	return c.LatLng
+}
+
+// Leg is the great-circle route from one point to another.
+type Leg struct {
+	From       Point   `json:"from"`
+	To         Point   `json:"to"`
+	DistanceKm float64 `json:"distanceKm"`
+	// Bearing is the initial compass bearing in degrees.
+	Bearing float64 `json:"bearing"`
+}
+
+// NewLeg measures the route from a to b.
+func NewLeg(a, b Point) Leg {
+	return Leg{From: a, To: b, DistanceKm: Distance(a.LatLng, b.LatLng), Bearing: Bearing(a.LatLng, b.LatLng)}
+}
+
+// Validate checks that p is a latitude and longitude in degrees.
+func Validate(p models.LatLng) error {
+	if math.IsNaN(p.Lat) || math.IsNaN(p.Lng) || math.Abs(p.Lat) > 90 || math.Abs(p.Lng) > 180 {
+		return fmt.Errorf("%w: lat must be within ±90 and lng within ±180", ErrInvalidPoint)
+	}
+	return nil
+}
+
+// Distance returns the great-circle distance between a and b in kilometers
+// by the haversine formula.
+func Distance(a, b models.LatLng) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(math.Min(1, h)))
+}
+
+// Bearing returns the initial compass bearing from a to b in degrees,
+// clockwise from north in [0, 360).
+func Bearing(a, b models.LatLng) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLng := radians(b.Lng - a.Lng)
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"country-search-api/pkg/models"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	london     = models.LatLng{Lat: 51.5074, Lng: -0.1278}
+	paris      = models.LatLng{Lat: 48.8566, Lng: 2.3522}
+	newYork    = models.LatLng{Lat: 40.7128, Lng: -74.0060}
+	losAngeles = models.LatLng{Lat: 34.0522, Lng: -118.2437}
+	newDelhi   = models.LatLng{Lat: 28.6, Lng: 77.2}
+	tokyo      = models.LatLng{Lat: 35.68, Lng: 139.75}
+)
+
+func TestDistance_KnownCities(t *testing.T) {
+	assert.InDelta(t, 344, Distance(london, paris), 1)
+	assert.InDelta(t, 3936, Distance(newYork, losAngeles), 5)
+	assert.InDelta(t, 5844, Distance(newDelhi, tokyo), 5)
+	assert.Equal(t, Distance(tokyo, newDelhi), Distance(newDelhi, tokyo))
+	assert.Zero(t, Distance(paris, paris))
+	// Half the circumference between antipodes.
+	assert.InDelta(t, 20015, Distance(models.LatLng{Lat: 0, Lng: 0}, models.LatLng{Lat: 0, Lng: 180}), 1)
+}
+
+func TestBearing_KnownCities(t *testing.T) {
+	assert.InDelta(t, 148.1, Bearing(london, paris), 0.1)
+	assert.InDelta(t, 273.7, Bearing(newYork, losAngeles), 0.1)
+	assert.InDelta(t, 0, Bearing(models.LatLng{}, models.LatLng{Lat: 10}), 1e-9)
+	assert.InDelta(t, 270, Bearing(models.LatLng{}, models.LatLng{Lng: -10}), 1e-9)
+}
+
+func TestValidateAndParseAnchor(t *testing.T) {
+	assert.NoError(t, Validate(models.LatLng{Lat: -90, Lng: 180}))
+	assert.ErrorIs(t, Validate(models.LatLng{Lat: 91}), ErrInvalidPoint)
+	assert.ErrorIs(t, Validate(models.LatLng{Lng: -181}), ErrInvalidPoint)
+
+	anchor, err := ParseAnchor("")
+	assert.NoError(t, err)
+	assert.Equal(t, AnchorCountry, anchor)
+	_, err = ParseAnchor("centroid")
+	assert.ErrorIs(t, err, ErrInvalidAnchor)
+}
+
+func at(cca3 string, lat, lng float64) models.CountryDetails {
+	return models.CountryDetails{CCA3: cca3, Name: models.CountryName{Common: cca3}, LatLng: &models.LatLng{Lat: lat, Lng: lng}}
+}
+
+func TestIndex_NearestAcrossAntimeridian(t *testing.T) {
+	ix := NewIndex([]models.CountryDetails{
+		at("FJI", -18, 175),
+		at("TON", -20, -175),
+		at("NZL", -41, 174),
+		at("AUS", -27, 133),
+		{CCA3: "ATA"}, // no coordinates
+	}, AnchorCountry)
+	assert.Equal(t, 4, ix.Len())
+
+	hits := ix.Nearest(models.LatLng{Lat: -19, Lng: -178}, 2)
+	assert.Equal(t, "TON", hits[0].CCA3)
+	assert.Equal(t, "FJI", hits[1].CCA3)
+	assert.Less(t, hits[0].DistanceKm, hits[1].DistanceKm)
+
+	assert.Len(t, ix.Nearest(models.LatLng{}, 10), 4)
+	assert.Empty(t, ix.Nearest(models.LatLng{}, 0))
+}
+
+func TestIndex_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	random := func() models.LatLng {
+		return models.LatLng{Lat: rng.Float64()*180 - 90, Lng: rng.Float64()*360 - 180}
+	}
+
+	var countries []models.CountryDetails
+	for i := 0; i < 300; i++ {
+		p := random()
+		countries = append(countries, at(string(rune('A'+i%26))+string(rune('A'+i/26)), p.Lat, p.Lng))
+	}
+	ix := NewIndex(countries, AnchorCountry)
+
+	for i := 0; i < 50; i++ {
+		q := random()
+		want := make([]float64, 0, len(countries))
+		for _, c := range countries {
+			want = append(want, Distance(q, *c.LatLng))
+		}
+		sort.Float64s(want)
+
+		hits := ix.Nearest(q, 5)
+		for j, hit := range hits {
+			assert.InDelta(t, want[j], hit.DistanceKm, 1e-6)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package geo
+
+import (
+	"container/heap"
+	"country-search-api/pkg/models"
+	"math"
+	"sort"
+)
+
+// Point is a country placed at the coordinates of an anchor.
+type Point struct {
+	CCA3    string        `json:"cca3"`
+	Name    string        `json:"name"`
+	Capital string        `json:"capital,omitempty"`
+	LatLng  models.LatLng `json:"latlng"`
+}
+
+// PointOf places c at the coordinates of anchor, reporting false when they
+// are unknown.
+func PointOf(c models.CountryDetails, anchor Anchor) (Point, bool) {
+	p := anchor.Of(c)
+	if p == nil {
+		return Point{}, false
+	}
+	point := Point{CCA3: c.CCA3, Name: c.Name.Common, LatLng: *p}
+	if anchor == AnchorCapital && len(c.Capitals) > 0 {
+		point.Capital = c.Capitals[0]
+	}
+	return point, true
+}
+
+// Hit is a point found by a nearest-neighbor query.
+type Hit struct {
+	Point
+	DistanceKm float64 `json:"distanceKm"`
+}
+
+// Index is a k-d tree over countries placed on the unit sphere, so
+// distances behave the same on both sides of the antimeridian and near the
+// poles. It is immutable once built and safe for concurrent use.
+type Index struct {
+	root *kdNode
+	size int
+}
+
+type kdNode struct {
+	point       Point
+	v           [3]float64
+	axis        int
+	left, right *kdNode
+}
+
+// NewIndex indexes every country that has coordinates for anchor.
+func NewIndex(countries []models.CountryDetails, anchor Anchor) *Index {
+	nodes := make([]*kdNode, 0, len(countries))
+	for _, c := range countries {
+		point, ok := PointOf(c, anchor)
+		if !ok {
+			continue
+		}
+		nodes = append(nodes, &kdNode{point: point, v: unitVector(point.LatLng)})
+	}
+	return &Index{root: build(nodes, 0), size: len(nodes)}
+}
+
+func build(nodes []*kdNode, depth int) *kdNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	axis := depth % 3
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].v[axis] < nodes[j].v[axis] })
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	n.axis = axis
+	n.left = build(nodes[:mid], depth+1)
+	n.right = build(nodes[mid+1:], depth+1)
+	return n
+}
+
+// Nearest returns up to k indexed countries closest to p, nearest first.
+func (ix *Index) Nearest(p models.LatLng, k int) []Hit {
+	if k <= 0 || ix.root == nil {
+		return []Hit{}
+	}
+
+	target := unitVector(p)
+	best := &maxHeap{}
+	var search func(n *kdNode)
+	search = func(n *kdNode) {
+		if n == nil {
+			return
+		}
+		d := chord2(n.v, target)
+		if best.Len() < k {
+			heap.Push(best, candidate{node: n, dist: d})
+		} else if d < (*best)[0].dist {
+			(*best)[0] = candidate{node: n, dist: d}
+			heap.Fix(best, 0)
+		}
+
+		diff := target[n.axis] - n.v[n.axis]
+		near, far := n.left, n.right
+		if diff > 0 {
+			near, far = far, near
+		}
+		search(near)
+		// The far side can only hold closer points if the splitting plane
+		// is nearer than the worst point kept so far.
+		if best.Len() < k || diff*diff < (*best)[0].dist {
+			search(far)
+		}
+	}
+	search(ix.root)
+
+	hits := make([]Hit, best.Len())
+	for i := len(hits) - 1; i >= 0; i-- {
+		c := heap.Pop(best).(candidate)
+		hits[i] = Hit{Point: c.node.point, DistanceKm: Distance(p, c.node.point.LatLng)}
+	}
+	return hits
+}
+
+// Len returns how many countries are indexed.
+func (ix *Index) Len() int {
+	return ix.size
+}
+
+// unitVector places p on the unit sphere. The squared chord between two
+// such vectors grows with their great-circle distance.
+func unitVector(p models.LatLng) [3]float64 {
+	lat, lng := radians(p.Lat), radians(p.Lng)
+	return [3]float64{math.Cos(lat) * math.Cos(lng), math.Cos(lat) * math.Sin(lng), math.Sin(lat)}
+}
+
+func chord2(a, b [3]float64) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+type candidate struct {
+	node *kdNode
+	dist float64
+}
+
+// maxHeap keeps the k best candidates with the farthest on top.
+type maxHeap []candidate
+
+func (h maxHeap) Len() int           { return len(h) }
+func (h maxHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h maxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// rtreeFanout is how many children each R-tree node holds.
+const rtreeFanout = 16
+
+// Box is a longitude/latitude bounding box. MinLng may be below -180 and
+// MaxLng above 180 for shapes crossing the antimeridian.
+type Box struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+func emptyBox() Box {
+	return Box{MinLng: math.Inf(1), MinLat: math.Inf(1), MaxLng: math.Inf(-1), MaxLat: math.Inf(-1)}
+}
+
+func (b Box) extend(o Box) Box {
+	return Box{
+		MinLng: math.Min(b.MinLng, o.MinLng),
+		MinLat: math.Min(b.MinLat, o.MinLat),
+		MaxLng: math.Max(b.MaxLng, o.MaxLng),
+		MaxLat: math.Max(b.MaxLat, o.MaxLat),
+	}
+}
+
+func (b Box) contains(lng, lat float64) bool {
+	return lng >= b.MinLng && lng <= b.MaxLng && lat >= b.MinLat && lat <= b.MaxLat
+}
+
+// rtree is a static R-tree bulk loaded with Sort-Tile-Recursive packing. It
+// only answers which entries' boxes contain a point.
+type rtree struct {
+	root *rnode
+}
+
+type rnode struct {
+	box      Box
+	children []*rnode
+	// entry is the index of the indexed item in a leaf.
+	entry int
+}
+
+func newRTree(boxes []Box) *rtree {
+	if len(boxes) == 0 {
+		return &rtree{}
+	}
+
+	level := make([]*rnode, len(boxes))
+	for i, b := range boxes {
+		level[i] = &rnode{box: b, entry: i}
+	}
+	for len(level) > 1 {
+		level = pack(level)
+	}
+	return &rtree{root: level[0]}
+}
+
+// pack groups nodes into parents of up to rtreeFanout children, tiling
+// them into vertical slices by longitude and then by latitude within each
+// slice so that siblings lie close together.
+func pack(nodes []*rnode) []*rnode {
+	parents := int(math.Ceil(float64(len(nodes)) / rtreeFanout))
+	slices := int(math.Ceil(math.Sqrt(float64(parents))))
+	perSlice := slices * rtreeFanout
+
+	sort.Slice(nodes, func(i, j int) bool { return center(nodes[i].box).lng < center(nodes[j].box).lng })
+
+	var level []*rnode
+	for start := 0; start < len(nodes); start += perSlice {
+		slice := nodes[start:min(start+perSlice, len(nodes))]
+		sort.Slice(slice, func(i, j int) bool { return center(slice[i].box).lat < center(slice[j].box).lat })
+
+		for i := 0; i < len(slice); i += rtreeFanout {
+			parent := &rnode{box: emptyBox(), entry: -1}
+			parent.children = slice[i:min(i+rtreeFanout, len(slice))]
+			for _, child := range parent.children {
+				parent.box = parent.box.extend(child.box)
+			}
+			level = append(level, parent)
+		}
+	}
+	return level
+}
+
+// search calls visit with every entry whose box contains the point.
+func (t *rtree) search(lng, lat float64, visit func(entry int)) {
+	if t.root == nil {
+		return
+	}
+	stack := []*rnode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !n.box.contains(lng, lat) {
+			continue
+		}
+		if n.children == nil {
+			visit(n.entry)
+			continue
+		}
+		stack = append(stack, n.children...)
+	}
+}
+
+type point2 struct{ lng, lat float64 }
+
+func center(b Box) point2 {
+	return point2{lng: (b.MinLng + b.MaxLng) / 2, lat: (b.MinLat + b.MaxLat) / 2}
+}
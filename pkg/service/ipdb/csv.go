@@ -0,0 +1,122 @@
+package ipdb
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// ipRange maps the addresses from start to end, inclusive, to a country.
+type ipRange struct {
+	start, end netip.Addr
+	code       string
+}
+
+// rangeTable looks addresses up in sorted, non-overlapping ranges read from
+// a CSV file.
+type rangeTable struct {
+	ranges []ipRange
+}
+
+// parseCSV reads rows of either "start,end,code", as in the DB-IP and
+// IP2Location country databases, or "network,code" with a CIDR prefix.
+// Rows that do not start with an address, such as a header, are skipped.
+func parseCSV(data []byte) (*rangeTable, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+
+	t := &rangeTable{}
+	for line := 1; ; line++ {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+		}
+
+		rng, ok, err := parseRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidDatabase, line, err)
+		}
+		if ok {
+			t.ranges = append(t.ranges, rng)
+		}
+	}
+	if len(t.ranges) == 0 {
+		return nil, fmt.Errorf("%w: no address ranges", ErrInvalidDatabase)
+	}
+
+	sort.Slice(t.ranges, func(i, j int) bool { return t.ranges[i].start.Less(t.ranges[j].start) })
+	for i := 1; i < len(t.ranges); i++ {
+		prev, cur := t.ranges[i-1], t.ranges[i]
+		if prev.start.Is4() == cur.start.Is4() && !prev.end.Less(cur.start) {
+			return nil, fmt.Errorf("%w: ranges %s-%s and %s-%s overlap", ErrInvalidDatabase, prev.start, prev.end, cur.start, cur.end)
+		}
+	}
+	return t, nil
+}
+
+func parseRow(row []string) (ipRange, bool, error) {
+	for i := range row {
+		row[i] = strings.TrimSpace(row[i])
+	}
+
+	switch {
+	case len(row) >= 3:
+		start, err := netip.ParseAddr(row[0])
+		if err != nil {
+			return ipRange{}, false, nil
+		}
+		end, err := netip.ParseAddr(row[1])
+		if err != nil {
+			return ipRange{}, false, err
+		}
+		start, end = start.Unmap(), end.Unmap()
+		if start.Is4() != end.Is4() || end.Less(start) {
+			return ipRange{}, false, fmt.Errorf("invalid range %s-%s", start, end)
+		}
+		return ipRange{start: start, end: end, code: row[2]}, row[2] != "" && row[2] != "-", nil
+
+	case len(row) == 2:
+		prefix, err := netip.ParsePrefix(row[0])
+		if err != nil {
+			return ipRange{}, false, nil
+		}
+		prefix = prefix.Masked()
+		return ipRange{start: prefix.Addr(), end: lastAddr(prefix), code: row[1]}, row[1] != "" && row[1] != "-", nil
+
+	default:
+		return ipRange{}, false, nil
+	}
+}
+
+// lastAddr returns the highest address within prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for bit := prefix.Bits(); bit < len(b)*8; bit++ {
+		b[bit/8] |= 1 << (7 - bit%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+func (t *rangeTable) Lookup(addr netip.Addr) (string, bool) {
+	addr = addr.Unmap()
+	// Find the last range starting at or before addr.
+	i := sort.Search(len(t.ranges), func(i int) bool { return addr.Less(t.ranges[i].start) }) - 1
+	if i < 0 {
+		return "", false
+	}
+	rng := t.ranges[i]
+	if rng.start.Is4() != addr.Is4() || rng.end.Less(addr) {
+		return "", false
+	}
+	return rng.code, true
+}
@@ -0,0 +1,53 @@
+package ipdb
+
+import (
+	"bytes"
+	"country-search-api/pkg/service/filewatch"
+	"errors"
+	"net/netip"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidDatabase = errors.New("invalid IP database")
+	ErrNoDatabase      = errors.New("IP database not loaded")
+)
+
+// Database maps an IP address to the ISO 3166-1 code of its country.
+type Database interface {
+	Lookup(addr netip.Addr) (string, bool)
+}
+
+// Parse reads a MaxMind DB when data carries its metadata marker and a CSV
+// range database otherwise.
+func Parse(data []byte) (Database, error) {
+	// The metadata sits within the last 128KiB of a MaxMind DB.
+	if bytes.Contains(data[max(0, len(data)-128*1024):], metadataMarker) {
+		return parseMMDB(data)
+	}
+	return parseCSV(data)
+}
+
+// File is a Database read from a file that is reloaded by Watch whenever
+// the file changes. It is safe for concurrent use.
+type File struct {
+	*filewatch.File[Database]
+}
+
+// Open loads the .mmdb or .csv database at path.
+func Open(path string) (*File, error) {
+	parse := Parse
+	if strings.EqualFold(filepath.Ext(path), ".mmdb") {
+		parse = func(data []byte) (Database, error) { return parseMMDB(data) }
+	}
+	f, err := filewatch.Open(path, "IP database", parse)
+	if err != nil {
+		return nil, err
+	}
+	return &File{f}, nil
+}
+
+func (f *File) Lookup(addr netip.Addr) (string, bool) {
+	return f.Value().Lookup(addr)
+}
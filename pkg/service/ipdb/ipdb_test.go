@@ -0,0 +1,254 @@
+package ipdb
+
+import (
+	"bytes"
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The helpers below write just enough of the MaxMind DB format to build
+// test databases.
+
+func encode(kind int, size int, payload []byte) []byte {
+	var out []byte
+	ctrl := byte(0)
+	if kind <= 7 {
+		ctrl = byte(kind) << 5
+	}
+	if size < 29 {
+		out = append(out, ctrl|byte(size))
+	} else {
+		out = append(out, ctrl|29)
+	}
+	if kind > 7 {
+		out = append(out, byte(kind-7))
+	}
+	if size >= 29 {
+		out = append(out, byte(size-29))
+	}
+	return append(out, payload...)
+}
+
+func encString(s string) []byte { return encode(typeString, len(s), []byte(s)) }
+
+func encUint32(n uint32) []byte {
+	return encode(typeUint32, 4, []byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+}
+
+// encPointer points at offset 0 of the data section.
+func encPointer() []byte { return []byte{typePointer << 5, 0} }
+
+func encMap(pairs ...[]byte) []byte {
+	out := encode(typeMap, len(pairs)/2, nil)
+	for _, p := range pairs {
+		out = append(out, p...)
+	}
+	return out
+}
+
+type tnode struct {
+	child [2]*tnode
+	data  [2]int // index+1 of the data record, 0 if none
+}
+
+// writeMMDB builds an IPv6 database mapping each prefix to a record. A
+// record naming "registered:XX" only carries a registered country.
+func writeMMDB(recordSize int, networks map[string]string) []byte {
+	// The data section opens with the string "country", which every
+	// record's key points back to.
+	data := encString("country")
+	var offsets []int
+	root := &tnode{}
+
+	prefixes := make([]string, 0, len(networks))
+	for p := range networks {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	for _, p := range prefixes {
+		code := networks[p]
+		offsets = append(offsets, len(data))
+		if rest, ok := bytes.CutPrefix([]byte(code), []byte("registered:")); ok {
+			data = append(data, encMap(encString("registered_country"), encMap(encString("iso_code"), encString(string(rest))))...)
+		} else {
+			data = append(data, encMap(encPointer(), encMap(encString("iso_code"), encString(code)))...)
+		}
+
+		prefix := netip.MustParsePrefix(p)
+		bits := prefix.Bits()
+		ip := prefix.Addr().As16()
+		if prefix.Addr().Is4() {
+			// IPv4 lives under ::/96 rather than the ::ffff:0:0/96 As16 uses.
+			ip = [16]byte{}
+			copy(ip[12:], prefix.Addr().AsSlice())
+			bits += 96
+		}
+		n := root
+		for i := 0; i < bits; i++ {
+			bit := ip[i/8] >> (7 - i%8) & 1
+			if i == bits-1 {
+				n.data[bit] = len(offsets)
+				break
+			}
+			if n.child[bit] == nil {
+				n.child[bit] = &tnode{}
+			}
+			n = n.child[bit]
+		}
+	}
+
+	var nodes []*tnode
+	index := map[*tnode]int{}
+	for queue := []*tnode{root}; len(queue) > 0; queue = queue[1:] {
+		index[queue[0]] = len(nodes)
+		nodes = append(nodes, queue[0])
+		for _, c := range queue[0].child {
+			if c != nil {
+				queue = append(queue, c)
+			}
+		}
+	}
+	count := len(nodes)
+	record := func(n *tnode, bit int) uint32 {
+		switch {
+		case n.child[bit] != nil:
+			return uint32(index[n.child[bit]])
+		case n.data[bit] != 0:
+			return uint32(count + 16 + offsets[n.data[bit]-1])
+		default:
+			return uint32(count)
+		}
+	}
+
+	var tree []byte
+	for _, n := range nodes {
+		l, r := record(n, 0), record(n, 1)
+		switch recordSize {
+		case 24:
+			tree = append(tree, byte(l>>16), byte(l>>8), byte(l), byte(r>>16), byte(r>>8), byte(r))
+		case 28:
+			tree = append(tree, byte(l>>16), byte(l>>8), byte(l), byte(l>>24<<4)|byte(r>>24), byte(r>>16), byte(r>>8), byte(r))
+		case 32:
+			tree = append(tree, byte(l>>24), byte(l>>16), byte(l>>8), byte(l), byte(r>>24), byte(r>>16), byte(r>>8), byte(r))
+		}
+	}
+
+	out := append(tree, make([]byte, 16)...)
+	out = append(out, data...)
+	out = append(out, metadataMarker...)
+	out = append(out, encMap(
+		encString("node_count"), encUint32(uint32(count)),
+		encString("record_size"), encode(typeUint16, 1, []byte{byte(recordSize)}),
+		encString("ip_version"), encode(typeUint16, 1, []byte{6}),
+		encString("binary_format_major_version"), encode(typeUint16, 1, []byte{2}),
+		encString("database_type"), encString("Test-Country"),
+		encString("description"), encMap(encString("en"), encString("A database of twenty-nine bytes or more")),
+	)...)
+	return out
+}
+
+var networks = map[string]string{
+	"81.2.69.0/24":    "GB",
+	"81.2.70.0/23":    "GB",
+	"2.125.160.0/19":  "DE",
+	"175.16.199.0/24": "registered:CN",
+	"2001:218::/32":   "JP",
+}
+
+func lookup(db Database, ip string) string {
+	code, _ := db.Lookup(netip.MustParseAddr(ip))
+	return code
+}
+
+func TestMMDB_Lookup(t *testing.T) {
+	for _, size := range []int{24, 28, 32} {
+		db, err := Parse(writeMMDB(size, networks))
+		assert.NoError(t, err, size)
+
+		assert.Equal(t, "GB", lookup(db, "81.2.69.160"), size)
+		assert.Equal(t, "GB", lookup(db, "81.2.71.1"), size)
+		assert.Equal(t, "GB", lookup(db, "::ffff:81.2.69.1"), size)
+		assert.Equal(t, "DE", lookup(db, "2.125.191.255"), size)
+		assert.Equal(t, "CN", lookup(db, "175.16.199.7"), size)
+		assert.Equal(t, "JP", lookup(db, "2001:218:1::1"), size)
+		assert.Empty(t, lookup(db, "81.2.68.255"), size)
+		assert.Empty(t, lookup(db, "2001:219::1"), size)
+	}
+
+	for _, data := range [][]byte{nil, []byte("not a database"), append(make([]byte, 16), metadataMarker...)} {
+		_, err := parseMMDB(data)
+		assert.ErrorIs(t, err, ErrInvalidDatabase)
+	}
+}
+
+func TestCSV_Lookup(t *testing.T) {
+	db, err := Parse([]byte(`start,end,country
+1.0.0.0,1.0.0.255,AU
+1.0.4.0,1.0.7.255,AU
+"1.0.16.0","1.0.31.255","JP"
+2c0f:f248::,2c0f:f24f:ffff:ffff:ffff:ffff:ffff:ffff,ZA
+10.0.0.0/8,-
+::ffff:8.8.8.0,::ffff:8.8.8.255,US
+2a00:1450::/29,IE
+`))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "AU", lookup(db, "1.0.0.1"))
+	assert.Equal(t, "AU", lookup(db, "1.0.7.255"))
+	assert.Equal(t, "JP", lookup(db, "1.0.20.3"))
+	assert.Equal(t, "US", lookup(db, "8.8.8.8"))
+	assert.Equal(t, "ZA", lookup(db, "2c0f:f248::1"))
+	assert.Equal(t, "IE", lookup(db, "2a00:1457:ffff::1"))
+	assert.Empty(t, lookup(db, "1.0.1.0"))
+	assert.Empty(t, lookup(db, "10.1.2.3"))
+	assert.Empty(t, lookup(db, "::1"))
+	assert.Empty(t, lookup(db, "0.0.0.1"))
+
+	for _, data := range []string{"", "header only", "1.0.0.0,1.0.0.255,AU\n1.0.0.128,1.0.1.0,NZ", "1.0.0.9,1.0.0.1,AU"} {
+		_, err := Parse([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidDatabase, data)
+	}
+}
+
+func TestFile_ReloadsWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "countries.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("1.0.0.0,1.0.0.255,AU\n"), 0o600))
+
+	f, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "AU", lookup(f, "1.0.0.1"))
+
+	reloaded, err := f.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	assert.NoError(t, os.WriteFile(path, []byte("1.0.0.0,1.0.0.255,NZ\n"), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	reloaded, err = f.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "NZ", lookup(f, "1.0.0.1"))
+
+	// A broken file keeps the previous database.
+	assert.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+	_, err = f.Reload()
+	assert.ErrorIs(t, err, ErrInvalidDatabase)
+	assert.Equal(t, "NZ", lookup(f, "1.0.0.1"))
+
+	mmdbPath := filepath.Join(t.TempDir(), "countries.mmdb")
+	assert.NoError(t, os.WriteFile(mmdbPath, writeMMDB(24, networks), 0o600))
+	f, err = Open(mmdbPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "DE", lookup(f, "2.125.160.216"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.Watch(ctx, time.Millisecond) // returns once ctx is done
+}
@@ -0,0 +1,95 @@
+package locale
+
+import (
+	"country-search-api/pkg/models"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// MaxPreferences bounds how many languages a request may list.
+const MaxPreferences = 10
+
+// English is the language of the names records carry outside translations.
+const English = "eng"
+
+var ErrInvalidLanguage = errors.New("invalid language")
+
+// keys maps ISO 639-3 codes onto the different key upstream translations
+// use for them.
+var keys = map[string]string{
+	"fas": "per", // Persian
+}
+
+// Preferences returns the languages names should be given in, most
+// preferred first, as the ISO 639-3 codes that key translations. lang, a
+// comma-separated list of language tags such as "de" or "pt-BR", comes
+// first, followed by the languages of an Accept-Language header. A
+// malformed header is ignored; a malformed lang is an error.
+func Preferences(lang, acceptLanguage string) ([]string, error) {
+	var tags []language.Tag
+	for _, s := range strings.Split(lang, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		tag, err := language.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a language tag such as de or pt-BR", ErrInvalidLanguage, s)
+		}
+		tags = append(tags, tag)
+	}
+	if accepted, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil {
+		tags = append(tags, accepted...)
+	}
+
+	var langs []string
+	for _, tag := range tags {
+		base, confidence := tag.Base()
+		if confidence == language.No {
+			continue
+		}
+		code := base.ISO3()
+		if key, ok := keys[code]; ok {
+			code = key
+		}
+		if !slices.Contains(langs, code) {
+			langs = append(langs, code)
+		}
+		if len(langs) == MaxPreferences {
+			break
+		}
+	}
+	return langs, nil
+}
+
+// Name returns the name of c in the first of langs it is known in, from its
+// translations or its native names, and that language. It falls back to the
+// English name.
+func Name(c models.CountryDetails, langs []string) (models.NativeName, string) {
+	for _, lang := range langs {
+		if lang == English {
+			break
+		}
+		if n, ok := c.Translations[lang]; ok && n.Common != "" {
+			return n, lang
+		}
+		if n, ok := c.Name.Native[lang]; ok && n.Common != "" {
+			return n, lang
+		}
+	}
+	return models.NativeName{Common: c.Name.Common, Official: c.Name.Official}, English
+}
+
+// Localize returns c with its common and official names in the first of
+// langs it is known in. Translations and native names are left as they are.
+func Localize(c models.CountryDetails, langs []string) models.CountryDetails {
+	if len(langs) == 0 {
+		return c
+	}
+	name, _ := Name(c, langs)
+	c.Name.Common, c.Name.Official = name.Common, name.Official
+	return c
+}
@@ -0,0 +1,79 @@
+package locale
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var germany = models.CountryDetails{
+	Name: models.CountryName{
+		Common:   "Germany",
+		Official: "Federal Republic of Germany",
+		Native:   map[string]models.NativeName{"deu": {Common: "Deutschland", Official: "Bundesrepublik Deutschland"}},
+	},
+	Translations: map[string]models.NativeName{
+		"fra": {Common: "Allemagne", Official: "République fédérale d'Allemagne"},
+		"per": {Common: "آلمان", Official: "جمهوری فدرال آلمان"},
+	},
+}
+
+func TestPreferences(t *testing.T) {
+	langs, err := Preferences("", "pt-BR,de;q=0.8,en;q=0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"por", "deu", "eng"}, langs)
+
+	// lang comes before the header, and duplicates are dropped.
+	langs, err = Preferences("fr, de", "de-AT,fr;q=0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fra", "deu"}, langs)
+
+	// Persian is keyed "per" upstream rather than its ISO 639-3 code.
+	langs, err = Preferences("fa", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"per"}, langs)
+
+	langs, err = Preferences("", "")
+	assert.NoError(t, err)
+	assert.Empty(t, langs)
+}
+
+func TestPreferences_Malformed(t *testing.T) {
+	// A malformed header is ignored.
+	langs, err := Preferences("", "de;q=x,,;")
+	assert.NoError(t, err)
+	assert.Empty(t, langs)
+
+	_, err = Preferences("not a language", "")
+	assert.ErrorIs(t, err, ErrInvalidLanguage)
+}
+
+func TestName(t *testing.T) {
+	name, lang := Name(germany, []string{"fra", "deu"})
+	assert.Equal(t, "Allemagne", name.Common)
+	assert.Equal(t, "fra", lang)
+
+	// Native names are used when there is no translation.
+	name, lang = Name(germany, []string{"jpn", "deu"})
+	assert.Equal(t, "Deutschland", name.Common)
+	assert.Equal(t, "deu", lang)
+
+	// English ends the search even if a later language is known.
+	name, lang = Name(germany, []string{"eng", "fra"})
+	assert.Equal(t, "Germany", name.Common)
+	assert.Equal(t, English, lang)
+
+	name, lang = Name(germany, []string{"jpn"})
+	assert.Equal(t, "Federal Republic of Germany", name.Official)
+	assert.Equal(t, English, lang)
+}
+
+func TestLocalize(t *testing.T) {
+	c := Localize(germany, []string{"per"})
+	assert.Equal(t, "آلمان", c.Name.Common)
+	assert.Equal(t, "جمهوری فدرال آلمان", c.Name.Official)
+	assert.Equal(t, germany.Translations, c.Translations)
+
+	assert.Equal(t, germany, Localize(germany, nil))
+}
@@ -0,0 +1,49 @@
+package money
+
+import "time"
+
+// Amount is a sum of money formatted to the minor units of its currency.
+type Amount struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+	// Country is set when the currency was chosen as the currency of a
+	// country.
+	Country string `json:"country,omitempty"`
+}
+
+// Conversion is the result of converting an amount between currencies.
+type Conversion struct {
+	From Amount `json:"from"`
+	To   Amount `json:"to"`
+	// Rate is how many units of To one unit of From buys.
+	Rate Decimal   `json:"rate"`
+	Base string    `json:"base"`
+	AsOf time.Time `json:"asOf,omitzero"`
+}
+
+// Convert converts amount from one currency to another at rates. The result
+// is computed exactly and rounded once, halves away from zero, to the minor
+// units of the target currency.
+func Convert(rates Rates, amount Decimal, from, to string) (Conversion, error) {
+	from, err := ParseCode(from)
+	if err != nil {
+		return Conversion{}, err
+	}
+	to, err = ParseCode(to)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	return Conversion{
+		From: Amount{Amount: amount.String(), Currency: from},
+		To:   Amount{Amount: amount.Mul(rate).StringFixed(MinorUnits(to)), Currency: to},
+		Rate: rate,
+		Base: rates.Base,
+		AsOf: rates.AsOf,
+	}, nil
+}
@@ -0,0 +1,159 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of decimal places a non-terminating value, such
+// as a cross rate, is printed with.
+const RateScale = 12
+
+// maxExponent bounds the exponent ParseDecimal accepts, so that input such
+// as "1e999999999" cannot allocate a huge number.
+const maxExponent = 100
+
+var ErrInvalidAmount = errors.New("invalid decimal amount")
+
+// Decimal is an exact decimal number backed by a big.Rat. Arithmetic never
+// rounds; only formatting does. The zero value is 0.
+type Decimal struct {
+	rat *big.Rat
+}
+
+// ParseDecimal parses a decimal such as "100", "-0.25" or "1.5e3". Fractions
+// and hexadecimal, which big.Rat would also accept, are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	mantissa, exponent, hasExp := strings.Cut(strings.ToLower(s), "e")
+	if !isDecimal(mantissa) {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if hasExp {
+		exp, err := strconv.Atoi(exponent)
+		if err != nil || exp > maxExponent || exp < -maxExponent {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return Decimal{rat: r}, nil
+}
+
+// isDecimal reports whether s is an optionally signed run of digits with at
+// most one decimal point.
+func isDecimal(s string) bool {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	digits, point := 0, false
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.' && !point:
+			point = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// MustParseDecimal is ParseDecimal for constants, panicking on error.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// Cmp compares d and e, returning -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.value().Cmp(e.value())
+}
+
+// Mul returns d × e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Mul(d.value(), e.value())}
+}
+
+// Quo returns d ÷ e exactly. It panics if e is zero.
+func (d Decimal) Quo(e Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Quo(d.value(), e.value())}
+}
+
+// StringFixed formats d with exactly places decimal places, rounding halves
+// away from zero.
+func (d Decimal) StringFixed(places int) string {
+	return d.value().FloatString(places)
+}
+
+// String formats d exactly when it has a terminating decimal expansion and
+// rounded to RateScale places otherwise.
+func (d Decimal) String() string {
+	places, ok := terminatingPlaces(d.value().Denom())
+	if !ok {
+		return strings.TrimRight(strings.TrimRight(d.StringFixed(RateScale), "0"), ".")
+	}
+	return d.StringFixed(places)
+}
+
+// terminatingPlaces returns how many decimal places a fraction with
+// denominator denom needs, which is finite only when its prime factors are
+// 2 and 5.
+func terminatingPlaces(denom *big.Int) (int, bool) {
+	n := new(big.Int).Set(denom)
+	twos := int(n.TrailingZeroBits())
+	n.Rsh(n, uint(twos))
+
+	fives, five, rem := 0, big.NewInt(5), new(big.Int)
+	for {
+		q, r := new(big.Int).QuoRem(n, five, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		n, fives = q, fives+1
+	}
+	return max(twos, fives), n.IsInt64() && n.Int64() == 1
+}
+
+// MarshalJSON writes d as a JSON string so that no precision is lost to
+// clients parsing numbers as floats.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a JSON number or string.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
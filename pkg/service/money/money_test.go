@@ -0,0 +1,130 @@
+package money
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := map[string]string{
+		"100":      "100",
+		"-0.25":    "-0.25",
+		" +1.50 ":  "1.5",
+		".5":       "0.5",
+		"1.5e3":    "1500",
+		"2.5E-2":   "0.025",
+		"0.000001": "0.000001",
+	}
+	for in, want := range tests {
+		d, err := ParseDecimal(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, d.String(), in)
+	}
+
+	for _, bad := range []string{"", "-", ".", "1/3", "0x10", "1.2.3", "1e", "1e999999999", "NaN", "1,000"} {
+		_, err := ParseDecimal(bad)
+		assert.ErrorIs(t, err, ErrInvalidAmount, bad)
+	}
+}
+
+func TestDecimal_IsExact(t *testing.T) {
+	// 0.1 + 0.2 style drift cannot happen: 0.1 × 3 is exactly 0.3.
+	d := MustParseDecimal("0.1").Mul(MustParseDecimal("3"))
+	assert.Equal(t, "0.3", d.String())
+	assert.Zero(t, d.Cmp(MustParseDecimal("0.3")))
+
+	third := MustParseDecimal("1").Quo(MustParseDecimal("3"))
+	assert.Equal(t, "0.333333333333", third.String())
+	assert.Equal(t, "1", third.Mul(MustParseDecimal("3")).String())
+
+	assert.Equal(t, "2.68", MustParseDecimal("2.675").StringFixed(2))
+	assert.Equal(t, "-2.68", MustParseDecimal("-2.675").StringFixed(2))
+	assert.Equal(t, "0", Decimal{}.String())
+}
+
+const frankfurter = `{"amount": 1.0, "base": "EUR", "date": "2026-10-16", "rates": {"INR": 97.45, "JPY": "163.2", "USD": 1.1, "kwd": "0.3371"}}`
+
+func TestConvert(t *testing.T) {
+	rates, err := ParseRates([]byte(frankfurter))
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", rates.Base)
+	assert.Equal(t, time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), rates.AsOf)
+
+	// 100 INR is 100 × 163.2 / 97.45 = 167.470497691... JPY, which has no
+	// minor units.
+	c, err := Convert(rates, MustParseDecimal("100"), "inr", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, Amount{Amount: "100", Currency: "INR"}, c.From)
+	assert.Equal(t, Amount{Amount: "167", Currency: "JPY"}, c.To)
+	assert.Equal(t, "1.674704976911", c.Rate.String())
+	assert.Equal(t, "EUR", c.Base)
+
+	c, err = Convert(rates, MustParseDecimal("10"), "USD", "KWD")
+	assert.NoError(t, err)
+	assert.Equal(t, "3.065", c.To.Amount) // three minor units
+
+	c, err = Convert(rates, MustParseDecimal("1.10"), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.00", c.To.Amount)
+	assert.Equal(t, "1.1", c.From.Amount)
+
+	_, err = Convert(rates, MustParseDecimal("1"), "GBP", "EUR")
+	assert.ErrorIs(t, err, ErrNoRate)
+	_, err = Convert(rates, MustParseDecimal("1"), "EUR", "ABC")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestParseRates_Invalid(t *testing.T) {
+	for _, data := range []string{
+		``,
+		`{"base": "EUR", "rates": {"INR": "1/3"}}`,
+		`{"base": "EUR", "rates": {"INR": 0}}`,
+		`{"base": "EUR", "rates": {"XYZ1": 1}}`,
+		`{"base": "", "rates": {}}`,
+		`{"base": "EUR", "date": "16/10/2026", "rates": {}}`,
+	} {
+		_, err := ParseRates([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidRates, data)
+	}
+
+	rates, err := ParseRates([]byte(`{"base": "USD", "timestamp": 1760572800, "rates": {"EUR": 0.9}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC), rates.AsOf)
+}
+
+func TestFile_ReloadsWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"INR": 97}}`), 0o600))
+
+	f, err := Open(path)
+	assert.NoError(t, err)
+	rates, _ := f.Rates(context.Background())
+	assert.Equal(t, "97", rates.Quotes["INR"].String())
+
+	reloaded, err := f.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"base": "EUR", "rates": {"INR": 98.5}}`), 0o600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	reloaded, err = f.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	rates, _ = f.Rates(context.Background())
+	assert.Equal(t, "98.5", rates.Quotes["INR"].String())
+
+	// A broken file keeps the previous rates.
+	assert.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+	_, err = f.Reload()
+	assert.ErrorIs(t, err, ErrInvalidRates)
+	rates, _ = f.Rates(context.Background())
+	assert.Equal(t, "98.5", rates.Quotes["INR"].String())
+
+	_, err = Open(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
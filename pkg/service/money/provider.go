@@ -0,0 +1,38 @@
+package money
+
+import (
+	"context"
+	"country-search-api/pkg/service/filewatch"
+)
+
+// Provider supplies the current exchange rates.
+type Provider interface {
+	Rates(ctx context.Context) (Rates, error)
+}
+
+// Static is a Provider of fixed rates, for tests and for deployments that
+// pin their rates.
+type Static Rates
+
+func (s Static) Rates(context.Context) (Rates, error) {
+	return Rates(s), nil
+}
+
+// File is a Provider reading rates from a JSON file that is reloaded by
+// Watch whenever the file changes. It is safe for concurrent use.
+type File struct {
+	*filewatch.File[Rates]
+}
+
+// Open loads the rates file at path.
+func Open(path string) (*File, error) {
+	f, err := filewatch.Open(path, "exchange rates", ParseRates)
+	if err != nil {
+		return nil, err
+	}
+	return &File{f}, nil
+}
+
+func (f *File) Rates(context.Context) (Rates, error) {
+	return f.Value(), nil
+}
@@ -0,0 +1,107 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+)
+
+var (
+	ErrInvalidRates    = errors.New("invalid exchange rates")
+	ErrNoRates         = errors.New("exchange rates not configured")
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrNoRate          = errors.New("no exchange rate")
+)
+
+// Rates are exchange rates against a base currency: Quotes["INR"] is how
+// many rupees one unit of Base buys.
+type Rates struct {
+	Base   string
+	AsOf   time.Time
+	Quotes map[string]Decimal
+}
+
+// ParseRates reads rates in the JSON layout used by Frankfurter and similar
+// services, with each rate given as a number or a string:
+//
+//	{"base": "EUR", "date": "2026-10-16", "rates": {"INR": 97.45, "JPY": "163.2"}}
+//
+// A Unix "timestamp" may stand in for the date.
+func ParseRates(data []byte) (Rates, error) {
+	var raw struct {
+		Base      string             `json:"base"`
+		Date      string             `json:"date"`
+		Timestamp int64              `json:"timestamp"`
+		Rates     map[string]Decimal `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Rates{}, fmt.Errorf("%w: %w", ErrInvalidRates, err)
+	}
+
+	base, err := ParseCode(raw.Base)
+	if err != nil {
+		return Rates{}, fmt.Errorf("%w: base: %w", ErrInvalidRates, err)
+	}
+	r := Rates{Base: base, Quotes: make(map[string]Decimal, len(raw.Rates)+1)}
+
+	switch {
+	case raw.Date != "":
+		if r.AsOf, err = time.Parse(time.DateOnly, raw.Date); err != nil {
+			return Rates{}, fmt.Errorf("%w: date %q is not YYYY-MM-DD", ErrInvalidRates, raw.Date)
+		}
+	case raw.Timestamp != 0:
+		r.AsOf = time.Unix(raw.Timestamp, 0).UTC()
+	}
+
+	for code, rate := range raw.Rates {
+		canonical, err := ParseCode(code)
+		if err != nil {
+			return Rates{}, fmt.Errorf("%w: %w", ErrInvalidRates, err)
+		}
+		if rate.Sign() <= 0 {
+			return Rates{}, fmt.Errorf("%w: rate for %s must be positive", ErrInvalidRates, code)
+		}
+		r.Quotes[canonical] = rate
+	}
+	r.Quotes[base] = MustParseDecimal("1")
+	return r, nil
+}
+
+// Rate returns how many units of to one unit of from buys, crossing through
+// the base currency.
+func (r Rates) Rate(from, to string) (Decimal, error) {
+	fromRate, ok := r.Quotes[from]
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w for %s", ErrNoRate, from)
+	}
+	toRate, ok := r.Quotes[to]
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w for %s", ErrNoRate, to)
+	}
+	return toRate.Quo(fromRate), nil
+}
+
+// ParseCode validates an ISO 4217 code such as "inr" and returns it in
+// canonical form.
+func ParseCode(code string) (string, error) {
+	unit, err := currency.ParseISO(strings.TrimSpace(code))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return unit.String(), nil
+}
+
+// MinorUnits returns the number of decimal places amounts in the currency
+// with the ISO 4217 code are given in, such as 2 for INR and 0 for JPY.
+func MinorUnits(code string) int {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
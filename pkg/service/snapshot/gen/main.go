@@ -0,0 +1,39 @@
+// Command gen downloads the full REST Countries dataset into a snapshot file
+// for the offline country service.
+package main
+
+import (
+	"bytes"
+	"context"
+	http_client "country-search-api/pkg/service/client"
+	"country-search-api/pkg/service/country"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	out := flag.String("o", "countries.json", "file to write the snapshot to")
+	baseURL := flag.String("base-url", "https://restcountries.com/v3.1", "REST Countries v3.1 API root")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	body, err := country.FetchSnapshot(ctx, http_client.NewHTTPClient(30*time.Second, nil), *baseURL)
+	if err != nil {
+		log.Fatalf("unable to fetch country snapshot: %v", err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		log.Fatalf("unable to format country snapshot: %v", err)
+	}
+	indented.WriteByte('\n')
+
+	if err := os.WriteFile(*out, indented.Bytes(), 0o644); err != nil {
+		log.Fatalf("unable to write country snapshot: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+// Package snapshot provides the REST Countries v3.1 dataset the offline and
+// hybrid country services read. A copy is compiled into the binary so the
+// service works without network access; a fresher one can be loaded from a
+// file.
+//
+// The embedded countries.json is a small seed covering a handful of
+// countries. Run go generate in this package to replace it with the full
+// upstream dataset before building for air-gapped use.
+package snapshot
+
+import (
+	_ "embed"
+	"os"
+)
+
+//go:generate go run ./gen -o countries.json
+
+//go:embed countries.json
+var embedded []byte
+
+// Embedded returns the snapshot compiled into the binary.
+func Embedded() []byte {
+	return embedded
+}
+
+// Load returns the snapshot at path, or the embedded one when path is empty.
+func Load(path string) ([]byte, error) {
+	if path == "" {
+		return Embedded(), nil
+	}
+	return os.ReadFile(path)
+}
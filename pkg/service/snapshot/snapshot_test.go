@@ -0,0 +1,35 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedded_IsCountryArray(t *testing.T) {
+	var countries []map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(Embedded(), &countries))
+	assert.NotEmpty(t, countries)
+	for _, c := range countries {
+		assert.Contains(t, c, "cca3")
+		assert.Contains(t, c, "name")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	data, err := Load("")
+	assert.NoError(t, err)
+	assert.Equal(t, Embedded(), data)
+
+	path := filepath.Join(t.TempDir(), "countries.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`[]`), 0o644))
+	data, err = Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`[]`), data)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
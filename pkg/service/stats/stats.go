@@ -0,0 +1,160 @@
+package stats
+
+import (
+	"country-search-api/pkg/models"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultTop and MaxTop bound how many countries Top returns.
+	DefaultTop = 10
+	MaxTop     = 250
+)
+
+var ErrInvalidQuery = errors.New("invalid statistics query")
+
+// Dimension is what countries are grouped by.
+type Dimension string
+
+const (
+	ByRegion    Dimension = "regions"
+	BySubregion Dimension = "subregions"
+	// ByContinent counts a country on several continents, such as Russia,
+	// in each of them.
+	ByContinent Dimension = "continents"
+)
+
+// ParseDimension parses "regions", "subregions" or "continents".
+func ParseDimension(s string) (Dimension, error) {
+	switch d := Dimension(strings.ToLower(s)); d {
+	case ByRegion, BySubregion, ByContinent:
+		return d, nil
+	}
+	return "", fmt.Errorf("%w: group by regions, subregions or continents", ErrInvalidQuery)
+}
+
+// Member is a country singled out within a group.
+type Member struct {
+	CCA3       string `json:"cca3"`
+	Name       string `json:"name"`
+	Population int64  `json:"population"`
+}
+
+// Group aggregates the countries of one region, subregion or continent.
+type Group struct {
+	Name       string  `json:"name"`
+	Countries  int     `json:"countries"`
+	Population int64   `json:"population"`
+	Area       float64 `json:"area"`
+	// Density is the group's population over its area; MedianDensity is
+	// the median of its countries' densities.
+	Density       float64 `json:"density"`
+	MedianDensity float64 `json:"medianDensity"`
+	MostPopulous  *Member `json:"mostPopulous,omitempty"`
+	LeastPopulous *Member `json:"leastPopulous,omitempty"`
+}
+
+// Report holds the groups of one dimension, most populous first, and the
+// totals over every country.
+type Report struct {
+	World  Group   `json:"world"`
+	Groups []Group `json:"groups"`
+}
+
+// Summary is the aggregation of one version of the dataset.
+type Summary struct {
+	countries []models.CountryDetails
+	reports   map[Dimension]Report
+}
+
+// New aggregates countries along every dimension.
+func New(countries []models.CountryDetails) *Summary {
+	world := aggregate("World", countries)
+	s := &Summary{countries: countries, reports: make(map[Dimension]Report, 3)}
+	for _, by := range []Dimension{ByRegion, BySubregion, ByContinent} {
+		s.reports[by] = Report{World: world, Groups: groupBy(countries, groupsOf(by))}
+	}
+	return s
+}
+
+// Report returns the groups of the by dimension.
+func (s *Summary) Report(by Dimension) Report {
+	return s.reports[by]
+}
+
+func groupsOf(by Dimension) func(models.CountryDetails) []string {
+	switch by {
+	case BySubregion:
+		return func(c models.CountryDetails) []string { return []string{c.Subregion} }
+	case ByContinent:
+		return func(c models.CountryDetails) []string { return c.Continents }
+	default:
+		return func(c models.CountryDetails) []string { return []string{c.Region} }
+	}
+}
+
+// groupBy aggregates countries under each of the names names returns for
+// them. Countries without a name are left out.
+func groupBy(countries []models.CountryDetails, names func(models.CountryDetails) []string) []Group {
+	members := map[string][]models.CountryDetails{}
+	for _, c := range countries {
+		for _, name := range names(c) {
+			if name != "" {
+				members[name] = append(members[name], c)
+			}
+		}
+	}
+
+	groups := make([]Group, 0, len(members))
+	for name, countries := range members {
+		groups = append(groups, aggregate(name, countries))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Population != groups[j].Population {
+			return groups[i].Population > groups[j].Population
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+func aggregate(name string, countries []models.CountryDetails) Group {
+	g := Group{Name: name, Countries: len(countries)}
+	var densities []float64
+	for _, c := range countries {
+		g.Population += c.Population
+		g.Area += c.Area
+		if c.Area > 0 {
+			densities = append(densities, c.Density())
+		}
+
+		m := &Member{CCA3: c.CCA3, Name: c.Name.Common, Population: c.Population}
+		if g.MostPopulous == nil || c.Population > g.MostPopulous.Population {
+			g.MostPopulous = m
+		}
+		if g.LeastPopulous == nil || c.Population < g.LeastPopulous.Population {
+			g.LeastPopulous = m
+		}
+	}
+	if g.Area > 0 {
+		g.Density = float64(g.Population) / g.Area
+	}
+	g.MedianDensity = median(densities)
+	return g
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	slices.Sort(values)
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return (values[mid-1] + values[mid]) / 2
+}
@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func country(cca3, region, subregion string, continents []string, population int64, area float64) models.CountryDetails {
+	return models.CountryDetails{
+		Name: models.CountryName{Common: cca3}, CCA3: cca3,
+		Region: region, Subregion: subregion, Continents: continents,
+		Population: population, Area: area,
+	}
+}
+
+var countries = []models.CountryDetails{
+	country("RUS", "Europe", "Eastern Europe", []string{"Europe", "Asia"}, 144, 17098),
+	country("UKR", "Europe", "Eastern Europe", []string{"Europe"}, 44, 603),
+	country("FRA", "Europe", "Western Europe", []string{"Europe"}, 67, 551),
+	country("MCO", "Europe", "Western Europe", []string{"Europe"}, 39, 2),
+	country("JPN", "Asia", "Eastern Asia", []string{"Asia"}, 125, 377),
+	country("ATA", "Antarctic", "", []string{"Antarctica"}, 1, 0),
+}
+
+func TestReport(t *testing.T) {
+	s := New(countries)
+
+	regions := s.Report(ByRegion)
+	assert.Equal(t, Group{
+		Name: "World", Countries: 6, Population: 420, Area: 18631,
+		Density: 420.0 / 18631, MedianDensity: 67.0 / 551,
+		MostPopulous:  &Member{CCA3: "RUS", Name: "RUS", Population: 144},
+		LeastPopulous: &Member{CCA3: "ATA", Name: "ATA", Population: 1},
+	}, regions.World)
+
+	assert.Len(t, regions.Groups, 3)
+	europe := regions.Groups[0]
+	assert.Equal(t, "Europe", europe.Name)
+	assert.Equal(t, 4, europe.Countries)
+	assert.Equal(t, int64(294), europe.Population)
+	assert.InDelta(t, 18254, europe.Area, 1e-9)
+	// Densities 0.0084, 0.073, 0.12 and 19.5: the median is between the two
+	// middle ones.
+	assert.InDelta(t, (44.0/603+67.0/551)/2, europe.MedianDensity, 1e-9)
+	assert.Equal(t, "MCO", europe.LeastPopulous.CCA3)
+	assert.Equal(t, "Asia", regions.Groups[1].Name)
+	assert.Equal(t, "Antarctic", regions.Groups[2].Name)
+	assert.Zero(t, regions.Groups[2].Density)
+
+	// Antarctica has no subregion and is left out.
+	subregions := s.Report(BySubregion)
+	assert.Len(t, subregions.Groups, 3)
+	assert.Equal(t, "Eastern Europe", subregions.Groups[0].Name)
+
+	// Russia counts towards both Europe and Asia.
+	continents := s.Report(ByContinent)
+	assert.Equal(t, "Europe", continents.Groups[0].Name)
+	assert.Equal(t, "Asia", continents.Groups[1].Name)
+	assert.Equal(t, int64(269), continents.Groups[1].Population)
+	assert.Equal(t, "RUS", continents.Groups[1].MostPopulous.CCA3)
+}
+
+func TestTop(t *testing.T) {
+	s := New(countries)
+
+	top, err := s.Top(TopQuery{Field: "Population", N: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []Ranked{
+		{Rank: 1, CCA3: "RUS", Name: "RUS", Value: 144},
+		{Rank: 2, CCA3: "JPN", Name: "JPN", Value: 125},
+		{Rank: 3, CCA3: "FRA", Name: "FRA", Value: 67},
+	}, top)
+
+	// Antarctica has no area, so no density.
+	top, err = s.Top(TopQuery{Field: "density", Ascending: true})
+	assert.NoError(t, err)
+	assert.Len(t, top, 5)
+	assert.Equal(t, "RUS", top[0].CCA3)
+	assert.Equal(t, "MCO", top[4].CCA3)
+
+	top, err = s.Top(TopQuery{Field: "area", Continent: "asia", N: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"RUS", "JPN"}, []string{top[0].CCA3, top[1].CCA3})
+
+	top, err = s.Top(TopQuery{Field: "population", Subregion: "Western Europe", Region: "Europe"})
+	assert.NoError(t, err)
+	assert.Len(t, top, 2)
+
+	top, err = s.Top(TopQuery{Field: "borders", Region: "Nowhere"})
+	assert.NoError(t, err)
+	assert.Empty(t, top)
+	assert.NotNil(t, top)
+
+	for _, q := range []TopQuery{{Field: "gdp"}, {Field: "area", N: -1}, {Field: "area", N: MaxTop + 1}} {
+		_, err := s.Top(q)
+		assert.ErrorIs(t, err, ErrInvalidQuery, q)
+	}
+}
+
+func TestTop_TiesShareRank(t *testing.T) {
+	s := New([]models.CountryDetails{
+		country("BBB", "", "", nil, 5, 1),
+		country("AAA", "", "", nil, 5, 1),
+		country("CCC", "", "", nil, 1, 1),
+	})
+
+	top, err := s.Top(TopQuery{Field: "population"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Ranked{
+		{Rank: 1, CCA3: "AAA", Name: "AAA", Value: 5},
+		{Rank: 1, CCA3: "BBB", Name: "BBB", Value: 5},
+		{Rank: 3, CCA3: "CCC", Name: "CCC", Value: 1},
+	}, top)
+}
+
+func TestParseDimension(t *testing.T) {
+	by, err := ParseDimension("Continents")
+	assert.NoError(t, err)
+	assert.Equal(t, ByContinent, by)
+
+	_, err = ParseDimension("countries")
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
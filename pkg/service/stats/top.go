@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"country-search-api/pkg/models"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// fields maps each field Top ranks by onto its value, reporting false for
+// countries the value is undefined for, such as density without an area.
+var fields = map[string]func(models.CountryDetails) (float64, bool){
+	"population": func(c models.CountryDetails) (float64, bool) { return float64(c.Population), true },
+	"area":       func(c models.CountryDetails) (float64, bool) { return c.Area, c.Area > 0 },
+	"density":    func(c models.CountryDetails) (float64, bool) { return c.Density(), c.Area > 0 },
+	"borders":    func(c models.CountryDetails) (float64, bool) { return float64(len(c.Borders)), true },
+	"languages":  func(c models.CountryDetails) (float64, bool) { return float64(len(c.Languages)), true },
+	"timezones":  func(c models.CountryDetails) (float64, bool) { return float64(len(c.Timezones)), true },
+}
+
+// Fields returns the fields Top ranks by, sorted.
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TopQuery selects the countries Top ranks.
+type TopQuery struct {
+	// Field is one of Fields.
+	Field string
+	// N is how many countries to return; zero means DefaultTop.
+	N int
+	// Ascending ranks the smallest first.
+	Ascending bool
+	// Region, Subregion and Continent optionally narrow the countries
+	// ranked, matched case-insensitively.
+	Region, Subregion, Continent string
+}
+
+// Ranked is a country's place in a Top ranking.
+type Ranked struct {
+	Rank  int     `json:"rank"`
+	CCA3  string  `json:"cca3"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Top ranks the countries matching q by q.Field, largest first unless
+// q.Ascending. Equal values share a rank and are ordered by name.
+func (s *Summary) Top(q TopQuery) ([]Ranked, error) {
+	value, ok := fields[strings.ToLower(q.Field)]
+	if !ok {
+		return nil, fmt.Errorf("%w: field must be one of %s", ErrInvalidQuery, strings.Join(Fields(), ", "))
+	}
+	if q.N == 0 {
+		q.N = DefaultTop
+	}
+	if q.N < 1 || q.N > MaxTop {
+		return nil, fmt.Errorf("%w: n must be between 1 and %d", ErrInvalidQuery, MaxTop)
+	}
+
+	ranked := []Ranked{}
+	for _, c := range s.countries {
+		if !q.matches(c) {
+			continue
+		}
+		if v, ok := value(c); ok {
+			ranked = append(ranked, Ranked{CCA3: c.CCA3, Name: c.Name.Common, Value: v})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Value != ranked[j].Value {
+			return (ranked[i].Value < ranked[j].Value) == q.Ascending
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+		if i > 0 && ranked[i].Value == ranked[i-1].Value {
+			ranked[i].Rank = ranked[i-1].Rank
+		}
+	}
+	return ranked[:min(q.N, len(ranked))], nil
+}
+
+func (q TopQuery) matches(c models.CountryDetails) bool {
+	if q.Region != "" && !strings.EqualFold(q.Region, c.Region) {
+		return false
+	}
+	if q.Subregion != "" && !strings.EqualFold(q.Subregion, c.Subregion) {
+		return false
+	}
+	if q.Continent != "" && !slices.ContainsFunc(c.Continents, func(name string) bool { return strings.EqualFold(q.Continent, name) }) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package timezone
+
+import (
+	"country-search-api/pkg/models"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OverlapDays is how far ahead BusinessOverlap looks for shared hours.
+const OverlapDays = 7
+
+var ErrInvalidHours = errors.New("invalid business hours")
+
+// BusinessHours is the working day, Monday to Friday, in minutes after
+// local midnight.
+type BusinessHours struct {
+	Start, End int
+}
+
+// DefaultBusinessHours runs from 09:00 to 17:00.
+var DefaultBusinessHours = BusinessHours{Start: 9 * 60, End: 17 * 60}
+
+// ParseBusinessHours parses start and end times such as "09:00" and
+// "17:30"; empty values keep the defaults.
+func ParseBusinessHours(start, end string) (BusinessHours, error) {
+	hours := DefaultBusinessHours
+	for _, f := range []struct {
+		value string
+		dst   *int
+	}{{start, &hours.Start}, {end, &hours.End}} {
+		if f.value == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", f.value)
+		if err != nil {
+			return BusinessHours{}, fmt.Errorf("%w: %q is not HH:MM", ErrInvalidHours, f.value)
+		}
+		*f.dst = t.Hour()*60 + t.Minute()
+	}
+	if hours.End <= hours.Start {
+		return BusinessHours{}, fmt.Errorf("%w: end must be after start", ErrInvalidHours)
+	}
+	return hours, nil
+}
+
+func (h BusinessHours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", h.Start/60, h.Start%60, h.End/60, h.End%60)
+}
+
+// Window is a span of time, in UTC, when both countries are at work.
+type Window struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Minutes int       `json:"minutes"`
+}
+
+// Party is one side of an overlap, placed in the zone of its capital.
+type Party struct {
+	CCA3 string `json:"cca3"`
+	Name string `json:"name"`
+	Zone string `json:"zone"`
+}
+
+// Overlap lists the business hours two countries share.
+type Overlap struct {
+	From          Party    `json:"from"`
+	To            Party    `json:"to"`
+	BusinessHours string   `json:"businessHours"`
+	Windows       []Window `json:"windows"`
+}
+
+// BusinessOverlap returns the windows within the next OverlapDays, counted
+// from now, when the capitals of a and b are both within business hours.
+// A window already open at now is included.
+func BusinessOverlap(a, b models.CountryDetails, hours BusinessHours, now time.Time) (Overlap, bool) {
+	za, okA := CapitalZone(a, now)
+	zb, okB := CapitalZone(b, now)
+	if !okA || !okB {
+		return Overlap{}, false
+	}
+
+	until := now.Add(OverlapDays * 24 * time.Hour)
+	windows := []Window{}
+	for _, w := range intersect(workdays(za.Location, hours, now, until), workdays(zb.Location, hours, now, until)) {
+		if w.End.After(now) && w.Start.Before(until) {
+			windows = append(windows, w)
+		}
+	}
+
+	return Overlap{
+		From:          Party{CCA3: a.CCA3, Name: a.Name.Common, Zone: za.Location.String()},
+		To:            Party{CCA3: b.CCA3, Name: b.Name.Common, Zone: zb.Location.String()},
+		BusinessHours: hours.String(),
+		Windows:       windows,
+	}, true
+}
+
+// workdays lists the business hours in loc on each weekday from the day
+// before now to the day after until, in order.
+func workdays(loc *time.Location, hours BusinessHours, now, until time.Time) []Window {
+	var spans []Window
+	local := now.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day()-1, 0, 0, 0, 0, loc)
+	for ; !day.After(until.Add(24 * time.Hour)); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, hours.Start, 0, 0, loc)
+		end := time.Date(day.Year(), day.Month(), day.Day(), 0, hours.End, 0, 0, loc)
+		spans = append(spans, Window{Start: start.UTC(), End: end.UTC()})
+	}
+	return spans
+}
+
+// intersect returns the overlaps of two ordered lists of disjoint spans.
+func intersect(a, b []Window) []Window {
+	var out []Window
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start := maxTime(a[i].Start, b[j].Start)
+		end := minTime(a[i].End, b[j].End)
+		if start.Before(end) {
+			out = append(out, Window{Start: start, End: end, Minutes: int(end.Sub(start).Minutes())})
+		}
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
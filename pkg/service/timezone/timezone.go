@@ -0,0 +1,230 @@
+package timezone
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/geo"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidOffset = errors.New("invalid UTC offset")
+	ErrNoTimezone    = errors.New("country has no known timezone")
+)
+
+// LocalTime is the wall clock of one timezone of a country.
+type LocalTime struct {
+	// Timezone is the offset as listed in the country record, such as
+	// "UTC+10:00".
+	Timezone string `json:"timezone"`
+	// Zone is the IANA zone the offset was mapped to, or Timezone itself
+	// when none of the country's zones match it.
+	Zone         string    `json:"zone"`
+	Time         time.Time `json:"time"`
+	UTCOffset    string    `json:"utcOffset"`
+	Abbreviation string    `json:"abbreviation"`
+	DST          bool      `json:"dst"`
+}
+
+// CountryTime is the current local time of a country's capital and of each
+// of its timezones.
+type CountryTime struct {
+	CCA3        string      `json:"cca3"`
+	Name        string      `json:"name"`
+	Capital     string      `json:"capital,omitempty"`
+	CapitalTime *LocalTime  `json:"capitalTime,omitempty"`
+	Timezones   []LocalTime `json:"timezones"`
+}
+
+// Zone is a timezone of a country resolved to a location.
+type Zone struct {
+	Label    string
+	Location *time.Location
+}
+
+// Now returns the local time at now in the capital and every timezone of c.
+func Now(c models.CountryDetails, now time.Time) CountryTime {
+	ct := CountryTime{CCA3: c.CCA3, Name: c.Name.Common, Timezones: []LocalTime{}}
+	for _, z := range Zones(c, now) {
+		ct.Timezones = append(ct.Timezones, localTime(z, now))
+	}
+	if len(c.Capitals) > 0 {
+		ct.Capital = c.Capitals[0]
+	}
+	if z, ok := CapitalZone(c, now); ok {
+		lt := localTime(z, now)
+		ct.CapitalTime = &lt
+	}
+	return ct
+}
+
+// localTime reports DST against the standard offset rather than IsDST, which
+// is false in Irish summer because tzdata models Irish winter time as
+// negative DST.
+func localTime(z Zone, now time.Time) LocalTime {
+	t := now.In(z.Location)
+	abbr, offset := t.Zone()
+	return LocalTime{
+		Timezone:     z.Label,
+		Zone:         z.Location.String(),
+		Time:         t,
+		UTCOffset:    FormatOffset(offset),
+		Abbreviation: abbr,
+		DST:          offset > standardOffset(z.Location, now.Year()),
+	}
+}
+
+// Zones maps each UTC offset listed for c onto the IANA zone of c with that
+// standard offset nearest the capital. Offsets no zone of c matches become
+// fixed zones; unparsable offsets are skipped.
+func Zones(c models.CountryDetails, now time.Time) []Zone {
+	entries := countryZones(c.CCA2)
+	ref := c.CapitalLatLng
+	if ref == nil {
+		ref = c.LatLng
+	}
+
+	zones := make([]Zone, 0, len(c.Timezones))
+	for _, label := range c.Timezones {
+		offset, err := ParseOffset(label)
+		if err != nil {
+			continue
+		}
+
+		var matching []zoneEntry
+		for _, e := range entries {
+			if loc, ok := location(e.name); ok && standardOffset(loc, now.Year()) == offset {
+				matching = append(matching, e)
+			}
+		}
+		if e, ok := nearest(matching, ref); ok {
+			loc, _ := location(e.name)
+			zones = append(zones, Zone{Label: label, Location: loc})
+			continue
+		}
+		zones = append(zones, Zone{Label: label, Location: time.FixedZone(label, offset)})
+	}
+	return zones
+}
+
+// CapitalZone returns the IANA zone of c nearest its capital, falling back
+// to its first listed timezone when the capital's location is unknown.
+func CapitalZone(c models.CountryDetails, now time.Time) (Zone, bool) {
+	if c.CapitalLatLng != nil {
+		if e, ok := nearest(countryZones(c.CCA2), c.CapitalLatLng); ok {
+			if loc, ok := location(e.name); ok {
+				return Zone{Label: labelOf(loc, now), Location: loc}, true
+			}
+		}
+	}
+	zones := Zones(c, now)
+	if len(zones) == 0 {
+		return Zone{}, false
+	}
+	return zones[0], true
+}
+
+// labelOf formats the standard offset of loc the way country records list
+// timezones.
+func labelOf(loc *time.Location, now time.Time) string {
+	offset := standardOffset(loc, now.Year())
+	if offset == 0 {
+		return "UTC"
+	}
+	return "UTC" + FormatOffset(offset)
+}
+
+// nearest returns the entry closest to ref, or the first one when ref is
+// unknown.
+func nearest(entries []zoneEntry, ref *models.LatLng) (zoneEntry, bool) {
+	if len(entries) == 0 {
+		return zoneEntry{}, false
+	}
+	if ref == nil {
+		return entries[0], true
+	}
+
+	best, bestDist := entries[0], math.Inf(1)
+	for _, e := range entries {
+		if d := geo.Distance(*ref, models.LatLng{Lat: e.lat, Lng: e.lng}); d < bestDist {
+			best, bestDist = e, d
+		}
+	}
+	return best, true
+}
+
+// standardOffset is the offset of loc outside daylight saving time in year,
+// taken as the smaller of its January and July offsets.
+func standardOffset(loc *time.Location, year int) int {
+	_, jan := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+	_, jul := time.Date(year, time.July, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+	return min(jan, jul)
+}
+
+var locations sync.Map
+
+// location loads and caches the IANA zone name.
+func location(name string) (*time.Location, bool) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	locations.Store(name, loc)
+	return loc, true
+}
+
+// ParseOffset parses offsets such as "UTC", "UTC+05:30" or "UTC-03:00" into
+// seconds east of UTC.
+func ParseOffset(label string) (int, error) {
+	s := strings.TrimSpace(label)
+	rest, ok := strings.CutPrefix(s, "UTC")
+	if !ok {
+		rest, ok = strings.CutPrefix(s, "GMT")
+	}
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidOffset, label)
+	}
+	if rest == "" {
+		return 0, nil
+	}
+
+	rest = strings.Replace(rest, "−", "-", 1)
+	sign := 1
+	switch rest[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrInvalidOffset, label)
+	}
+
+	hh, mm, _ := strings.Cut(rest[1:], ":")
+	hours, err := strconv.Atoi(hh)
+	if err != nil || hours > 14 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidOffset, label)
+	}
+	minutes := 0
+	if mm != "" {
+		if minutes, err = strconv.Atoi(mm); err != nil || minutes > 59 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidOffset, label)
+		}
+	}
+	return sign * (hours*3600 + minutes*60), nil
+}
+
+// FormatOffset formats seconds east of UTC as "+05:30".
+func FormatOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign, seconds = '-', -seconds
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, seconds/3600, seconds%3600/60)
+}
@@ -0,0 +1,146 @@
+package timezone
+
+import (
+	"country-search-api/pkg/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	australia = models.CountryDetails{
+		Name: models.CountryName{Common: "Australia"}, CCA2: "AU", CCA3: "AUS",
+		Capitals:      []string{"Canberra"},
+		CapitalLatLng: &models.LatLng{Lat: -35.27, Lng: 149.13},
+		Timezones:     []string{"UTC+05:00", "UTC+08:00", "UTC+10:00"},
+	}
+	india = models.CountryDetails{
+		Name: models.CountryName{Common: "India"}, CCA2: "IN", CCA3: "IND",
+		Capitals:      []string{"New Delhi"},
+		CapitalLatLng: &models.LatLng{Lat: 28.6, Lng: 77.2},
+		Timezones:     []string{"UTC+05:30"},
+	}
+	japan = models.CountryDetails{
+		Name: models.CountryName{Common: "Japan"}, CCA2: "JP", CCA3: "JPN",
+		Capitals:  []string{"Tokyo"},
+		Timezones: []string{"UTC+09:00"},
+	}
+)
+
+func TestNow_AustraliaInSummerAndWinter(t *testing.T) {
+	summer := time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+	ct := Now(australia, summer)
+
+	assert.Equal(t, "Canberra", ct.Capital)
+	assert.Equal(t, "Australia/Sydney", ct.CapitalTime.Zone)
+	assert.Equal(t, "UTC+10:00", ct.CapitalTime.Timezone)
+	assert.Equal(t, "2026-01-15T11:00:00+11:00", ct.CapitalTime.Time.Format(time.RFC3339))
+	assert.Equal(t, "+11:00", ct.CapitalTime.UTCOffset)
+	assert.Equal(t, "AEDT", ct.CapitalTime.Abbreviation)
+	assert.True(t, ct.CapitalTime.DST)
+
+	assert.Len(t, ct.Timezones, 3)
+	// No Australian zone keeps UTC+05:00; Heard Island gets a fixed zone.
+	assert.Equal(t, "UTC+05:00", ct.Timezones[0].Zone)
+	assert.Equal(t, "+05:00", ct.Timezones[0].UTCOffset)
+	assert.Equal(t, "Australia/Perth", ct.Timezones[1].Zone)
+	assert.False(t, ct.Timezones[1].DST)
+	assert.Equal(t, "Australia/Sydney", ct.Timezones[2].Zone)
+
+	winter := time.Date(2026, time.July, 15, 0, 0, 0, 0, time.UTC)
+	ct = Now(australia, winter)
+
+	assert.Equal(t, "2026-07-15T10:00:00+10:00", ct.CapitalTime.Time.Format(time.RFC3339))
+	assert.Equal(t, "AEST", ct.CapitalTime.Abbreviation)
+	assert.False(t, ct.CapitalTime.DST)
+}
+
+func TestNow_WithoutCapitalLocation(t *testing.T) {
+	ct := Now(japan, time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "Asia/Tokyo", ct.CapitalTime.Zone)
+	assert.Equal(t, 21, ct.CapitalTime.Time.Hour())
+
+	ct = Now(models.CountryDetails{CCA3: "XXX", Timezones: []string{"local"}}, time.Now())
+	assert.Nil(t, ct.CapitalTime)
+	assert.Empty(t, ct.Timezones)
+}
+
+func TestBusinessOverlap(t *testing.T) {
+	monday := time.Date(2026, time.January, 12, 0, 0, 0, 0, time.UTC)
+	overlap, ok := BusinessOverlap(india, japan, DefaultBusinessHours, monday)
+	assert.True(t, ok)
+
+	assert.Equal(t, "Asia/Kolkata", overlap.From.Zone)
+	assert.Equal(t, "Asia/Tokyo", overlap.To.Zone)
+	assert.Equal(t, "09:00-17:00", overlap.BusinessHours)
+	// Tokyo works 00:00-08:00 UTC and New Delhi 03:30-11:30 UTC.
+	assert.Len(t, overlap.Windows, 5)
+	assert.Equal(t, time.Date(2026, time.January, 12, 3, 30, 0, 0, time.UTC), overlap.Windows[0].Start)
+	assert.Equal(t, time.Date(2026, time.January, 12, 8, 0, 0, 0, time.UTC), overlap.Windows[0].End)
+	assert.Equal(t, 270, overlap.Windows[0].Minutes)
+	assert.Equal(t, time.Friday, overlap.Windows[4].Start.Weekday())
+
+	// Midway through Monday's overlap, it is still listed.
+	overlap, _ = BusinessOverlap(india, japan, DefaultBusinessHours, monday.Add(5*time.Hour))
+	assert.Equal(t, 12, overlap.Windows[0].Start.Day())
+
+	// Sydney and New York never share a 09:00-17:00 day.
+	newYork := models.CountryDetails{CCA2: "US", CCA3: "USA", CapitalLatLng: &models.LatLng{Lat: 40.7, Lng: -74}}
+	overlap, _ = BusinessOverlap(australia, newYork, DefaultBusinessHours, monday)
+	assert.Empty(t, overlap.Windows)
+
+	_, ok = BusinessOverlap(india, models.CountryDetails{CCA3: "ATA"}, DefaultBusinessHours, monday)
+	assert.False(t, ok)
+}
+
+func TestParseBusinessHours(t *testing.T) {
+	hours, err := ParseBusinessHours("08:30", "")
+	assert.NoError(t, err)
+	assert.Equal(t, BusinessHours{Start: 8*60 + 30, End: 17 * 60}, hours)
+
+	for _, bad := range [][2]string{{"9am", ""}, {"18:00", ""}, {"10:00", "10:00"}} {
+		_, err := ParseBusinessHours(bad[0], bad[1])
+		assert.ErrorIs(t, err, ErrInvalidHours, bad)
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := map[string]int{
+		"UTC":       0,
+		"UTC+05:30": 5*3600 + 30*60,
+		"UTC-03:00": -3 * 3600,
+		"UTC−09:30": -(9*3600 + 30*60),
+		"UTC+14:00": 14 * 3600,
+		"GMT+1":     3600,
+	}
+	for label, want := range tests {
+		got, err := ParseOffset(label)
+		assert.NoError(t, err, label)
+		assert.Equal(t, want, got, label)
+	}
+	assert.Equal(t, "+05:30", FormatOffset(5*3600+30*60))
+	assert.Equal(t, "-09:30", FormatOffset(-(9*3600 + 30*60)))
+	assert.Equal(t, "+00:00", FormatOffset(0))
+
+	for _, bad := range []string{"", "CET", "UTC05:00", "UTC+25:00", "UTC+05:99"} {
+		_, err := ParseOffset(bad)
+		assert.ErrorIs(t, err, ErrInvalidOffset, bad)
+	}
+}
+
+func TestParseISO6709(t *testing.T) {
+	lat, lng, ok := parseISO6709("-3352+15113")
+	assert.True(t, ok)
+	assert.InDelta(t, -33.867, lat, 0.001)
+	assert.InDelta(t, 151.217, lng, 0.001)
+
+	lat, lng, ok = parseISO6709("+404251-0740023")
+	assert.True(t, ok)
+	assert.InDelta(t, 40.714, lat, 0.001)
+	assert.InDelta(t, -74.006, lng, 0.001)
+
+	_, _, ok = parseISO6709("+40")
+	assert.False(t, ok)
+}
@@ -0,0 +1,104 @@
+package timezone
+
+import (
+	"bufio"
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+
+	// Embed the IANA time zone database so zones resolve on hosts without
+	// one installed.
+	_ "time/tzdata"
+)
+
+// zoneTab is the tzdb zone.tab, which lists the IANA zones of each country
+// with the location of the city that names them.
+//
+//go:embed zone.tab
+var zoneTab string
+
+// zoneEntry is one row of zone.tab.
+type zoneEntry struct {
+	name     string
+	lat, lng float64
+}
+
+var (
+	zonesOnce sync.Once
+	zonesByCC map[string][]zoneEntry
+)
+
+// countryZones returns the IANA zones of the country with the alpha-2 code
+// cca2, in zone.tab order.
+func countryZones(cca2 string) []zoneEntry {
+	zonesOnce.Do(func() {
+		zonesByCC = parseZoneTab(zoneTab)
+	})
+	return zonesByCC[strings.ToUpper(cca2)]
+}
+
+func parseZoneTab(tab string) map[string][]zoneEntry {
+	zones := make(map[string][]zoneEntry)
+	sc := bufio.NewScanner(strings.NewReader(tab))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		lat, lng, ok := parseISO6709(fields[1])
+		if !ok {
+			continue
+		}
+		zones[fields[0]] = append(zones[fields[0]], zoneEntry{name: fields[2], lat: lat, lng: lng})
+	}
+	return zones
+}
+
+// parseISO6709 reads zone.tab coordinates such as "+4230+00131" or
+// "-3352+15113" (±DDMM±DDDMM, optionally with seconds).
+func parseISO6709(s string) (float64, float64, bool) {
+	if len(s) < 2 {
+		return 0, 0, false
+	}
+	split := strings.IndexAny(s[1:], "+-") + 1
+	if split <= 0 {
+		return 0, 0, false
+	}
+	lat, ok1 := parseDMS(s[:split], 2)
+	lng, ok2 := parseDMS(s[split:], 3)
+	return lat, lng, ok1 && ok2
+}
+
+func parseDMS(s string, degreeDigits int) (float64, bool) {
+	if len(s) < 1+degreeDigits+2 {
+		return 0, false
+	}
+	sign := 1.0
+	if s[0] == '-' {
+		sign = -1
+	}
+	digits := s[1:]
+
+	var value float64
+	for i, scale := 0, 1.0; len(digits) > 0; i, scale = i+1, scale*60 {
+		n := 2
+		if i == 0 {
+			n = degreeDigits
+		}
+		if len(digits) < n {
+			return 0, false
+		}
+		part, err := strconv.Atoi(digits[:n])
+		if err != nil {
+			return 0, false
+		}
+		value += float64(part) / scale
+		digits = digits[n:]
+	}
+	return sign * value, true
+}
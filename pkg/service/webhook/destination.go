@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateDestination is returned when a webhook would be delivered to a
+// loopback, link-local, private or otherwise reserved address, which could
+// let a subscriber reach services that are not meant to be exposed.
+var ErrPrivateDestination = errors.New("webhook destination is not a public address")
+
+// RegistryOption configures a Registry.
+type RegistryOption func(*Registry)
+
+// AllowPrivateDestinations lets subscriptions point at loopback, link-local
+// and private addresses, for receivers on the same host or network.
+func AllowPrivateDestinations() RegistryOption {
+	return func(r *Registry) {
+		r.allowPrivate = true
+	}
+}
+
+// checkHost rejects hosts that are, or name, a non-public address. Other
+// host names are checked again when a delivery connects, since they may
+// resolve to anything.
+func checkHost(host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, ip)
+	}
+	return nil
+}
+
+// reserved lists the special-purpose ranges net.IP has no predicate for.
+var reserved = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),     // "this network"
+	mustCIDR("100.64.0.0/10"), // carrier-grade NAT shared address space
+	mustCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustCIDR("198.18.0.0/15"), // network benchmarking
+	mustCIDR("240.0.0.0/4"),   // reserved, including broadcast
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, n := range reserved {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewHTTPClient returns a client for delivering webhooks that refuses to
+// connect to non-public addresses unless allowPrivate is set. The check runs
+// on every connection, so it also covers host names resolving to private
+// addresses and redirects. Proxies from the environment are not used, since
+// the client would then only see the proxy's address.
+func NewHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateDestination, host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
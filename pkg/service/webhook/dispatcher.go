@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"country-search-api/pkg/logger"
+	"country-search-api/pkg/service/dataset"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body.
+	SignatureHeader = "X-Country-Signature"
+	EventIDHeader   = "X-Country-Event"
+
+	defaultAttempts = 5
+	defaultBackoff  = 500 * time.Millisecond
+	// maxDelivered bounds how many delivered events are remembered for
+	// deduplication.
+	maxDelivered = 10000
+	// maxDeadLetters bounds the dead-letter log.
+	maxDeadLetters = 100
+)
+
+// errPermanent marks a delivery failure that retrying cannot fix.
+var errPermanent = errors.New("permanent delivery failure")
+
+// DeadLetter records an event that could not be delivered.
+type DeadLetter struct {
+	Subscription string    `json:"subscription"`
+	URL          string    `json:"url"`
+	Event        Event     `json:"event"`
+	Attempts     int       `json:"attempts"`
+	Error        string    `json:"error"`
+	At           time.Time `json:"at"`
+}
+
+// Dispatcher delivers events to the subscriptions of a Registry, retrying
+// with exponential backoff and deduplicating events already delivered.
+type Dispatcher struct {
+	registry *Registry
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+
+	mu        sync.Mutex
+	delivered map[string]bool
+	order     []string
+	dead      []DeadLetter
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithRetry makes each delivery try up to attempts times, waiting backoff
+// and then doubling it between tries.
+func WithRetry(attempts int, backoff time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.attempts = attempts
+		d.backoff = backoff
+	}
+}
+
+func NewDispatcher(registry *Registry, client *http.Client, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		registry:  registry,
+		client:    client,
+		attempts:  defaultAttempts,
+		backoff:   defaultBackoff,
+		delivered: make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Notify delivers the events of update to every interested subscription
+// and waits for the deliveries to finish.
+func (d *Dispatcher) Notify(ctx context.Context, update dataset.Update) {
+	events := Events(update)
+	if len(events) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, sub := range d.registry.List() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, e := range events {
+				if sub.wants(e.CCA3) {
+					d.deliver(ctx, sub, e)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// DeadLetters returns the events that could not be delivered, newest first.
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dead := make([]DeadLetter, 0, len(d.dead))
+	for i := len(d.dead) - 1; i >= 0; i-- {
+		dead = append(dead, d.dead[i])
+	}
+	return dead
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, sub Subscription, e Event) {
+	key := sub.ID + ":" + e.ID
+	if !d.claim(key) {
+		logger.Log().Info("skipping duplicate webhook event:", "subscription", sub.ID, "event", e.ID)
+		return
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		d.deadLetter(sub, e, 0, err)
+		return
+	}
+
+	backoff := d.backoff
+	var attempt int
+	for attempt = 1; attempt <= d.attempts; attempt++ {
+		err = d.post(ctx, sub, e, body)
+		if err == nil {
+			logger.Log().Info("delivered webhook event:", "subscription", sub.ID, "event", e.ID, "attempt", attempt)
+			return
+		}
+		logger.Log().Warn("webhook delivery failed:", "subscription", sub.ID, "event", e.ID, "attempt", attempt, "error", err)
+		if errors.Is(err, errPermanent) || attempt == d.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			d.deadLetter(sub, e, attempt, ctx.Err())
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	d.deadLetter(sub, e, attempt, err)
+}
+
+func (d *Dispatcher) post(ctx context.Context, sub Subscription, e Event, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, e.ID)
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("receiver responded %s", resp.Status)
+	default:
+		return fmt.Errorf("%w: receiver responded %s", errPermanent, resp.Status)
+	}
+}
+
+// claim marks key as delivered, reporting false if it already was. The
+// oldest keys are forgotten once maxDelivered is reached.
+func (d *Dispatcher) claim(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.delivered[key] {
+		return false
+	}
+	d.delivered[key] = true
+	d.order = append(d.order, key)
+	if len(d.order) > maxDelivered {
+		delete(d.delivered, d.order[0])
+		d.order = d.order[1:]
+	}
+	return true
+}
+
+func (d *Dispatcher) deadLetter(sub Subscription, e Event, attempts int, err error) {
+	logger.Log().Error("giving up on webhook event:", "subscription", sub.ID, "event", e.ID, "attempts", attempts, "error", err)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dead = append(d.dead, DeadLetter{
+		Subscription: sub.ID,
+		URL:          sub.URL,
+		Event:        e,
+		Attempts:     attempts,
+		Error:        err.Error(),
+		At:           time.Now().UTC(),
+	})
+	if len(d.dead) > maxDeadLetters {
+		d.dead = d.dead[len(d.dead)-maxDeadLetters:]
+	}
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid signature of body.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/dataset"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Event notifies a subscriber that the summary of a country changed.
+type Event struct {
+	// ID identifies the change; the same change has the same ID, even when
+	// it is seen again by another version or after a restart.
+	ID string `json:"id"`
+	// Type is "country.added", "country.changed" or "country.removed".
+	Type     string          `json:"type"`
+	Version  uint64          `json:"version"`
+	At       time.Time       `json:"at"`
+	CCA3     string          `json:"cca3"`
+	Previous *models.Country `json:"previous,omitempty"`
+	Current  *models.Country `json:"current,omitempty"`
+}
+
+// Events turns a dataset update into events, one per country whose summary
+// view differs from the previous version. Changes to other fields alone do
+// not produce events.
+func Events(update dataset.Update) []Event {
+	var events []Event
+	for _, change := range update.Changes {
+		e := Event{
+			Type:    "country." + change.Kind,
+			Version: update.Version,
+			At:      update.At,
+			CCA3:    change.CCA3,
+		}
+		if change.Previous != nil {
+			prev := change.Previous.Summary()
+			e.Previous = &prev
+		}
+		if change.Current != nil {
+			cur := change.Current.Summary()
+			e.Current = &cur
+		}
+		if e.Previous != nil && e.Current != nil && *e.Previous == *e.Current {
+			continue
+		}
+
+		e.ID = eventID(e)
+		events = append(events, e)
+	}
+	return events
+}
+
+// eventID hashes the transition of e: the country and its summary before
+// and after. The dataset version is left out since it restarts with the
+// process, so the same change keeps its ID across restarts.
+func eventID(e Event) string {
+	b, _ := json.Marshal(struct {
+		CCA3     string
+		Previous *models.Country
+		Current  *models.Country
+	}{e.CCA3, e.Previous, e.Current})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
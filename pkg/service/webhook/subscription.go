@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidSubscription = errors.New("invalid webhook subscription")
+	ErrUnknownSubscription = errors.New("unknown webhook subscription")
+)
+
+// Subscription registers url to receive change events. Every delivery is
+// signed with Secret.
+type Subscription struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+	// Countries limits events to these alpha-3 codes; empty means every
+	// country.
+	Countries []string `json:"countries,omitempty"`
+}
+
+// wants reports whether s subscribes to events about cca3.
+func (s Subscription) wants(cca3 string) bool {
+	return len(s.Countries) == 0 || slices.Contains(s.Countries, cca3)
+}
+
+// Registry holds the webhook subscriptions.
+type Registry struct {
+	mu   sync.RWMutex
+	subs map[string]Subscription
+
+	allowPrivate bool
+}
+
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{subs: make(map[string]Subscription)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Add validates and stores sub, filling in a generated ID and secret when
+// they are empty. URLs pointing at loopback, link-local or private addresses
+// are refused unless the registry allows them. The stored subscription is
+// returned.
+func (r *Registry) Add(sub Subscription) (Subscription, error) {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Subscription{}, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+	if !r.allowPrivate {
+		if err := checkHost(u.Hostname()); err != nil {
+			return Subscription{}, fmt.Errorf("%w: %w", ErrInvalidSubscription, err)
+		}
+	}
+
+	// Normalize a copy so the caller's slice is left as it was.
+	sub.Countries = slices.Clone(sub.Countries)
+	for i, code := range sub.Countries {
+		sub.Countries[i] = strings.ToUpper(strings.TrimSpace(code))
+	}
+	if sub.ID == "" {
+		sub.ID = randomHex(8)
+	}
+	if sub.Secret == "" {
+		sub.Secret = randomHex(32)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subs[sub.ID]; ok {
+		return Subscription{}, fmt.Errorf("%w: duplicate id %q", ErrInvalidSubscription, sub.ID)
+	}
+	r.subs[sub.ID] = sub
+	return sub, nil
+}
+
+func (r *Registry) Remove(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subs[id]; !ok {
+		return ErrUnknownSubscription
+	}
+	delete(r.subs, id)
+	return nil
+}
+
+// List returns the subscriptions sorted by ID.
+func (r *Registry) List() []Subscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subs := make([]Subscription, 0, len(r.subs))
+	for _, s := range r.subs {
+		subs = append(subs, s)
+	}
+	slices.SortFunc(subs, func(a, b Subscription) int { return strings.Compare(a.ID, b.ID) })
+	return subs
+}
+
+// LoadFile reads a JSON array of subscriptions.
+func LoadFile(path string) ([]Subscription, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var subs []Subscription
+	if err := json.Unmarshal(b, &subs); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return subs, nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,184 @@
+package webhook
+
+import (
+	"context"
+	"country-search-api/pkg/models"
+	"country-search-api/pkg/service/dataset"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func capitalMoved() dataset.Update {
+	prev := models.CountryDetails{
+		Name: models.CountryName{Common: "Indonesia"}, CCA3: "IDN", Capitals: []string{"Jakarta"},
+		Currencies: []models.Currency{{Code: "IDR", Symbol: "Rp"}}, Population: 273523621,
+	}
+	next := prev
+	next.Capitals = []string{"Nusantara"}
+
+	borders := models.CountryDetails{Name: models.CountryName{Common: "Chile"}, CCA3: "CHL"}
+	return dataset.Update{
+		Version: 2,
+		Changes: []dataset.Change{
+			{CCA3: "IDN", Kind: "changed", Previous: &prev, Current: &next},
+			{CCA3: "CHL", Kind: "changed", Previous: &borders, Current: &borders},
+		},
+	}
+}
+
+func TestEvents_OnlySummaryChanges(t *testing.T) {
+	events := Events(capitalMoved())
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "country.changed", events[0].Type)
+	assert.Equal(t, "Jakarta", events[0].Previous.Capital)
+	assert.Equal(t, "Nusantara", events[0].Current.Capital)
+	assert.Equal(t, events[0].ID, Events(capitalMoved())[0].ID)
+
+	// A restarted process numbers versions anew, but the change keeps its ID.
+	again := capitalMoved()
+	again.Version = 1
+	assert.Equal(t, events[0].ID, Events(again)[0].ID)
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	_, err := r.Add(Subscription{URL: "ftp://example.com"})
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+
+	countries := []string{"idn"}
+	sub, err := r.Add(Subscription{URL: "https://example.com/hook", Countries: countries})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"idn"}, countries)
+	assert.NotEmpty(t, sub.ID)
+	assert.NotEmpty(t, sub.Secret)
+	assert.True(t, sub.wants("IDN"))
+	assert.False(t, sub.wants("CHL"))
+
+	assert.Len(t, r.List(), 1)
+	assert.NoError(t, r.Remove(sub.ID))
+	assert.ErrorIs(t, r.Remove(sub.ID), ErrUnknownSubscription)
+}
+
+func TestRegistry_RejectsPrivateDestinations(t *testing.T) {
+	r := NewRegistry()
+	for _, u := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://[fd00::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+		"http://[::ffff:100.100.100.200]/hook",
+		"http://198.18.0.1/hook",
+	} {
+		_, err := r.Add(Subscription{URL: u})
+		assert.ErrorIs(t, err, ErrInvalidSubscription, u)
+		assert.ErrorIs(t, err, ErrPrivateDestination, u)
+	}
+
+	_, err := NewRegistry(AllowPrivateDestinations()).Add(Subscription{URL: "http://127.0.0.1:8080/hook"})
+	assert.NoError(t, err)
+}
+
+func TestNewHTTPClient_RefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	_, err := NewHTTPClient(time.Second, false).Get(srv.URL)
+	assert.ErrorIs(t, err, ErrPrivateDestination)
+
+	resp, err := NewHTTPClient(time.Second, true).Get(srv.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestDispatcher_SignsAndDeduplicates(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.True(t, Verify("s3cret", body, r.Header.Get(SignatureHeader)))
+
+		var e Event
+		assert.NoError(t, json.Unmarshal(body, &e))
+		assert.Equal(t, e.ID, r.Header.Get(EventIDHeader))
+		mu.Lock()
+		received = append(received, e)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	r := NewRegistry(AllowPrivateDestinations())
+	_, err := r.Add(Subscription{URL: srv.URL, Secret: "s3cret"})
+	assert.NoError(t, err)
+	d := NewDispatcher(r, srv.Client())
+
+	d.Notify(context.Background(), capitalMoved())
+	d.Notify(context.Background(), capitalMoved())
+
+	assert.Len(t, received, 1)
+	assert.Equal(t, "IDN", received[0].CCA3)
+	assert.Empty(t, d.DeadLetters())
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewRegistry(AllowPrivateDestinations())
+	_, _ = r.Add(Subscription{URL: srv.URL})
+	d := NewDispatcher(r, srv.Client(), WithRetry(3, time.Millisecond))
+
+	d.Notify(context.Background(), capitalMoved())
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Empty(t, d.DeadLetters())
+}
+
+func TestDispatcher_DeadLetters(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	r := NewRegistry(AllowPrivateDestinations())
+	_, _ = r.Add(Subscription{ID: "flaky", URL: srv.URL + "/flaky"})
+	_, _ = r.Add(Subscription{ID: "gone", URL: srv.URL + "/gone"})
+	d := NewDispatcher(r, srv.Client(), WithRetry(2, time.Millisecond))
+
+	d.Notify(context.Background(), capitalMoved())
+
+	assert.Equal(t, int32(3), calls.Load()) // two tries for flaky, one for gone
+	dead := d.DeadLetters()
+	assert.Len(t, dead, 2)
+	attempts := map[string]int{}
+	for _, dl := range dead {
+		attempts[dl.Subscription] = dl.Attempts
+		assert.Equal(t, "IDN", dl.Event.CCA3)
+	}
+	assert.Equal(t, map[string]int{"flaky": 2, "gone": 1}, attempts)
+}